@@ -0,0 +1,132 @@
+package gofn
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RandSource source of random numbers used by the backoff strategies. *rand.Rand satisfies this interface.
+// NOTE: a source shared between goroutines must be safe for concurrent use, *rand.Rand is not.
+type RandSource interface {
+	// Int63n returns a non-negative random number in [0, n)
+	Int63n(n int64) int64
+}
+
+type globalRandSource struct{}
+
+func (globalRandSource) Int63n(n int64) int64 {
+	return rand.Int63n(n) //nolint:gosec
+}
+
+// Backoff strategy to calculate the delays between retries
+type Backoff interface {
+	// NextDelay returns the delay before the retry number `retry` (starting from 0).
+	// prevDelay is the delay returned for the previous retry (0 for the first retry).
+	NextDelay(retry int, prevDelay time.Duration, rnd RandSource) time.Duration
+}
+
+// BackoffFunc function implementing Backoff
+type BackoffFunc func(retry int, prevDelay time.Duration, rnd RandSource) time.Duration
+
+func (f BackoffFunc) NextDelay(retry int, prevDelay time.Duration, rnd RandSource) time.Duration {
+	return f(retry, prevDelay, rnd)
+}
+
+// BackoffConstant always returns the same delay
+func BackoffConstant(delay time.Duration) Backoff {
+	return BackoffFunc(func(int, time.Duration, RandSource) time.Duration {
+		return delay
+	})
+}
+
+// BackoffLinear returns delays increasing by the same amount: initial, initial+increment, initial+2*increment...
+func BackoffLinear(initial, increment time.Duration) Backoff {
+	return BackoffFunc(func(retry int, _ time.Duration, _ RandSource) time.Duration {
+		return initial + time.Duration(retry)*increment
+	})
+}
+
+// BackoffExponential returns delays multiplied by the multiplier: initial, initial*m, initial*m^2...
+func BackoffExponential(initial time.Duration, multiplier float64) Backoff {
+	return BackoffFunc(func(retry int, _ time.Duration, _ RandSource) time.Duration {
+		return expoDelay(initial, multiplier, retry)
+	})
+}
+
+// BackoffFullJitter returns random delays in [0, min(maxDelay, base*2^retry)].
+// This spreads the retries of many clients well, pass maxDelay 0 to set no limit.
+func BackoffFullJitter(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(retry int, _ time.Duration, rnd RandSource) time.Duration {
+		delay := capDelay(expoDelay(base, 2, retry), maxDelay) //nolint:mnd
+		return randDelay(rnd, 0, delay)
+	})
+}
+
+// BackoffEqualJitter returns random delays in [d/2, d] where d = min(maxDelay, base*2^retry).
+// Pass maxDelay 0 to set no limit.
+func BackoffEqualJitter(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(retry int, _ time.Duration, rnd RandSource) time.Duration {
+		delay := capDelay(expoDelay(base, 2, retry), maxDelay) //nolint:mnd
+		return randDelay(rnd, delay/2, delay)
+	})
+}
+
+// BackoffDecorrelatedJitter returns random delays in [base, prevDelay*3] capped by maxDelay.
+// Pass maxDelay 0 to set no limit.
+func BackoffDecorrelatedJitter(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(_ int, prevDelay time.Duration, rnd RandSource) time.Duration {
+		upper := base
+		if prevDelay > 0 && prevDelay <= math.MaxInt64/3 {
+			upper = Max(base, prevDelay*3) //nolint:mnd
+		} else if prevDelay > 0 {
+			upper = math.MaxInt64
+		}
+		return capDelay(randDelay(rnd, base, upper), maxDelay)
+	})
+}
+
+// BackoffFibonacci returns delays following the Fibonacci sequence: unit, unit, 2*unit, 3*unit, 5*unit...
+func BackoffFibonacci(unit time.Duration) Backoff {
+	return BackoffFunc(func(retry int, _ time.Duration, _ RandSource) time.Duration {
+		a, b := int64(1), int64(1)
+		for i := 0; i < retry; i++ {
+			if b > math.MaxInt64-a {
+				return math.MaxInt64
+			}
+			a, b = b, a+b
+		}
+		if a > math.MaxInt64/Max(int64(unit), 1) {
+			return math.MaxInt64
+		}
+		return time.Duration(a) * unit
+	})
+}
+
+// expoDelay calculates base*multiplier^retry without overflowing
+func expoDelay(base time.Duration, multiplier float64, retry int) time.Duration {
+	delay := float64(base) * math.Pow(multiplier, float64(retry))
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+func capDelay(delay, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// randDelay returns a random delay in [lower, upper]
+func randDelay(rnd RandSource, lower, upper time.Duration) time.Duration {
+	if upper <= lower {
+		return lower
+	}
+	n := int64(upper - lower)
+	if n < math.MaxInt64 {
+		n++
+	}
+	return lower + time.Duration(rnd.Int63n(n))
+}
@@ -0,0 +1,82 @@
+package gofn
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedRandSource always returns the max value
+type fixedRandSource struct{}
+
+func (fixedRandSource) Int63n(n int64) int64 {
+	return n - 1
+}
+
+// nolint
+func Test_Backoff(t *testing.T) {
+	ms := time.Millisecond
+	nextDelays := func(b Backoff, rnd RandSource, count int) []time.Duration {
+		delays := make([]time.Duration, count)
+		prevDelay := time.Duration(0)
+		for i := range delays {
+			delays[i] = b.NextDelay(i, prevDelay, rnd)
+			prevDelay = delays[i]
+		}
+		return delays
+	}
+
+	t.Run("deterministic backoffs", func(t *testing.T) {
+		assert.Equal(t, []time.Duration{5 * ms, 5 * ms, 5 * ms}, nextDelays(BackoffConstant(5*ms), nil, 3))
+		assert.Equal(t, []time.Duration{5 * ms, 7 * ms, 9 * ms}, nextDelays(BackoffLinear(5*ms, 2*ms), nil, 3))
+		assert.Equal(t, []time.Duration{4 * ms, 6 * ms, 9 * ms}, nextDelays(BackoffExponential(4*ms, 1.5), nil, 3))
+		assert.Equal(t, []time.Duration{ms, ms, 2 * ms, 3 * ms, 5 * ms, 8 * ms},
+			nextDelays(BackoffFibonacci(ms), nil, 6))
+		assert.Equal(t, time.Duration(math.MaxInt64), BackoffExponential(ms, 2).NextDelay(100, 0, nil))
+		assert.Equal(t, time.Duration(math.MaxInt64), BackoffFibonacci(ms).NextDelay(200, 0, nil))
+	})
+
+	t.Run("jitter backoffs", func(t *testing.T) {
+		rnd := fixedRandSource{}
+		assert.Equal(t, []time.Duration{10 * ms, 20 * ms, 40 * ms, 50 * ms},
+			nextDelays(BackoffFullJitter(10*ms, 50*ms), rnd, 4))
+		assert.Equal(t, []time.Duration{10 * ms, 20 * ms, 40 * ms, 50 * ms},
+			nextDelays(BackoffEqualJitter(10*ms, 50*ms), rnd, 4))
+		assert.Equal(t, []time.Duration{10 * ms, 30 * ms, 90 * ms, 100 * ms},
+			nextDelays(BackoffDecorrelatedJitter(10*ms, 100*ms), rnd, 4))
+
+		seeded := rand.New(rand.NewSource(1))
+		for _, d := range nextDelays(BackoffFullJitter(10*ms, 0), seeded, 10) {
+			assert.True(t, d >= 0)
+		}
+		for i, d := range nextDelays(BackoffEqualJitter(10*ms, 0), seeded, 10) {
+			upper := 10 * ms * time.Duration(1<<i)
+			assert.True(t, d >= upper/2 && d <= upper)
+		}
+		for _, d := range nextDelays(BackoffDecorrelatedJitter(10*ms, 200*ms), seeded, 10) {
+			assert.True(t, d >= 10*ms && d <= 200*ms)
+		}
+	})
+
+	t.Run("used by retry functions", func(t *testing.T) {
+		errTest := errors.New("test error")
+		var delays []time.Duration
+		onRetry := ExecRetryOnRetry(func(attempt int, err error, nextDelay time.Duration) {
+			delays = append(delays, nextDelay)
+		})
+
+		_ = ExecRetry(func() error { return errTest }, 4, time.Hour,
+			ExecRetryBackoff(BackoffFibonacci(ms)), ExecRetryDelayMax(2*ms), onRetry)
+		assert.Equal(t, []time.Duration{ms, ms, 2 * ms, 2 * ms}, delays)
+
+		// Injectable random source for the legacy jitter
+		delays = nil
+		_ = ExecRetry(func() error { return errTest }, 2, ms,
+			ExecRetryDelayExpoBackoff(ms), ExecRetryRandSource(fixedRandSource{}), onRetry)
+		assert.Equal(t, []time.Duration{ms, 3*ms - 1}, delays)
+	})
+}
@@ -0,0 +1,215 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type BatcherConfig struct {
+	maxConcurrentFlushes uint
+	errorHandler         func(error)
+}
+
+type BatcherOption func(*BatcherConfig)
+
+// BatcherMaxConcurrentFlushes sets the maximum number of flushes running at the same time (default is 1).
+// When the limit is reached, Add() blocks until a flush finishes.
+func BatcherMaxConcurrentFlushes(maxConcurrentFlushes uint) BatcherOption {
+	return func(config *BatcherConfig) {
+		config.maxConcurrentFlushes = maxConcurrentFlushes
+	}
+}
+
+// BatcherErrorHandler sets a handler to receive errors returned by the flush function
+func BatcherErrorHandler(errorHandler func(error)) BatcherOption {
+	return func(config *BatcherConfig) {
+		config.errorHandler = errorHandler
+	}
+}
+
+// Batcher collects items and flushes them in batches when the number of items reaches `maxSize`
+// or when `maxWait` has elapsed since the first item of the batch was added.
+// All methods are safe for concurrent use.
+type Batcher[T any] struct {
+	cfg         *BatcherConfig
+	flushFunc   func(ctx context.Context, items []T) error
+	maxSize     int
+	maxWait     time.Duration
+	mu          sync.Mutex
+	items       []T
+	timer       *time.Timer
+	timerSeq    uint64
+	closed      bool
+	limiterChan chan struct{}
+	flushWg     sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// NewBatcher creates a new batcher. Pass 0 as `maxWait` to flush by size only.
+// Call Close() to flush the remaining items when the batcher is no longer used.
+func NewBatcher[T any](
+	flushFunc func(ctx context.Context, items []T) error,
+	maxSize int,
+	maxWait time.Duration,
+	options ...BatcherOption,
+) *Batcher[T] {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+	cfg := &BatcherConfig{
+		maxConcurrentFlushes: 1,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	if cfg.maxConcurrentFlushes == 0 {
+		cfg.maxConcurrentFlushes = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Batcher[T]{
+		cfg:         cfg,
+		flushFunc:   flushFunc,
+		maxSize:     maxSize,
+		maxWait:     maxWait,
+		limiterChan: make(chan struct{}, cfg.maxConcurrentFlushes),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Add adds items to the current batch. In case the batch becomes full, it is flushed and the remaining
+// items are split into batches the same way as Chunk() does.
+func (b *Batcher[T]) Add(items ...T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBatcherClosed
+	}
+	b.items = append(b.items, items...)
+
+	var batches [][]T
+	if len(b.items) >= b.maxSize {
+		batches = Chunk(b.items, b.maxSize)
+		b.items = nil
+		if last := batches[len(batches)-1]; len(last) < b.maxSize {
+			b.items = append(make([]T, 0, b.maxSize), last...)
+			batches = batches[:len(batches)-1]
+		}
+		b.stopTimer()
+		b.flushWg.Add(len(batches))
+	}
+	if len(b.items) > 0 && b.timer == nil && b.maxWait > 0 {
+		b.timerSeq++
+		seq := b.timerSeq
+		b.timer = time.AfterFunc(b.maxWait, func() { b.onTimer(seq) })
+	}
+	b.mu.Unlock()
+
+	for _, batch := range batches {
+		b.flush(batch)
+	}
+	return nil
+}
+
+func (b *Batcher[T]) onTimer(seq uint64) {
+	b.mu.Lock()
+	if seq != b.timerSeq {
+		b.mu.Unlock()
+		return
+	}
+	b.timer = nil
+	items := b.takeItems()
+	b.mu.Unlock()
+
+	if len(items) > 0 {
+		b.flush(items)
+	}
+}
+
+// takeItems takes the items of the current batch, must be called with the lock held
+func (b *Batcher[T]) takeItems() []T {
+	items := b.items
+	b.items = nil
+	b.stopTimer()
+	if len(items) > 0 {
+		b.flushWg.Add(1)
+	}
+	return items
+}
+
+// stopTimer stops the timer of the current batch, must be called with the lock held
+func (b *Batcher[T]) stopTimer() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.timerSeq++
+}
+
+// flush flushes a batch in a new goroutine, blocks when the number of running flushes reaches the limit
+func (b *Batcher[T]) flush(items []T) {
+	b.limiterChan <- struct{}{}
+	go func() {
+		defer b.flushWg.Done()
+		defer func() {
+			<-b.limiterChan
+		}()
+
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = newPanicError(r, -1)
+				}
+			}()
+			return b.flushFunc(b.ctx, items)
+		}()
+		if err != nil && b.cfg.errorHandler != nil {
+			b.cfg.errorHandler(err)
+		}
+	}()
+}
+
+// Flush flushes the current batch immediately without waiting for it to be full
+func (b *Batcher[T]) Flush() {
+	b.mu.Lock()
+	items := b.takeItems()
+	b.mu.Unlock()
+
+	if len(items) > 0 {
+		b.flush(items)
+	}
+}
+
+// Close stops accepting new items, flushes the remaining items, and waits for all flushes to finish.
+// If the context is done before that, the context passed to the flush function is canceled and
+// the context error is returned.
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	b.mu.Lock()
+	var items []T
+	if !b.closed {
+		b.closed = true
+		items = b.takeItems()
+	}
+	b.mu.Unlock()
+
+	doneChan := make(chan struct{})
+	go func() {
+		if len(items) > 0 {
+			b.flush(items)
+		}
+		b.flushWg.Wait()
+		close(doneChan)
+	}()
+
+	select {
+	case <-doneChan:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err() // nolint: wrapcheck
+	}
+}
@@ -0,0 +1,118 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_Batcher(t *testing.T) {
+	errTest := errors.New("test error")
+
+	type flushedData struct {
+		mu      sync.Mutex
+		batches [][]int
+	}
+	newFlushFunc := func(data *flushedData) func(ctx context.Context, items []int) error {
+		return func(ctx context.Context, items []int) error {
+			data.mu.Lock()
+			data.batches = append(data.batches, items)
+			data.mu.Unlock()
+			return nil
+		}
+	}
+
+	t.Run("flush by size", func(t *testing.T) {
+		data := &flushedData{}
+		b := NewBatcher(newFlushFunc(data), 3, 0)
+		assert.Nil(t, b.Add(1, 2))
+		assert.Nil(t, b.Add(3))
+		assert.Nil(t, b.Add(4, 5, 6, 7, 8, 9, 10, 11))
+		assert.Nil(t, b.Close(context.Background()))
+		assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10, 11}}, data.batches)
+
+		assert.ErrorIs(t, b.Add(1), ErrBatcherClosed)
+		assert.Nil(t, b.Close(context.Background()))
+	})
+
+	t.Run("flush by time", func(t *testing.T) {
+		data := &flushedData{}
+		b := NewBatcher(newFlushFunc(data), 100, 20*time.Millisecond)
+		assert.Nil(t, b.Add(1, 2))
+		time.Sleep(50 * time.Millisecond)
+		assert.Nil(t, b.Add(3))
+		data.mu.Lock()
+		assert.Equal(t, [][]int{{1, 2}}, data.batches)
+		data.mu.Unlock()
+
+		b.Flush()
+		assert.Nil(t, b.Close(context.Background()))
+		assert.Equal(t, [][]int{{1, 2}, {3}}, data.batches)
+	})
+
+	t.Run("concurrent adds and flushes", func(t *testing.T) {
+		var total atomic.Int32
+		var running, maxRunning atomic.Int32
+		b := NewBatcher(func(ctx context.Context, items []int) error {
+			n := running.Add(1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			total.Add(int32(len(items)))
+			return nil
+		}, 10, 10*time.Millisecond, BatcherMaxConcurrentFlushes(2))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 25; j++ {
+					assert.Nil(t, b.Add(j))
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Nil(t, b.Close(context.Background()))
+		assert.Equal(t, int32(250), total.Load())
+		assert.True(t, maxRunning.Load() <= 2)
+	})
+
+	t.Run("error handler", func(t *testing.T) {
+		var mu sync.Mutex
+		var errs []error
+		b := NewBatcher(func(ctx context.Context, items []int) error {
+			if items[0] == 0 {
+				panic(errTest)
+			}
+			return errTest
+		}, 2, 0, BatcherErrorHandler(func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}))
+		assert.Nil(t, b.Add(0, 1, 2, 3))
+		assert.Nil(t, b.Close(context.Background()))
+		assert.Equal(t, 2, len(errs))
+		assert.True(t, ContainBy(errs, func(e error) bool { return errors.Is(e, ErrPanic) }))
+	})
+
+	t.Run("close timed out", func(t *testing.T) {
+		b := NewBatcher(func(ctx context.Context, items []int) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, 10, 0)
+		assert.Nil(t, b.Add(1))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+	})
+}
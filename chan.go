@@ -0,0 +1,248 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// chanSend sends a value to the channel, returns false if the context is done first
+func chanSend[T any](ctx context.Context, ch chan<- T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// SliceToChan sends the slice items to a new channel. The channel is closed when all items are sent
+// or when the context is done.
+func SliceToChan[T any, S ~[]T](ctx context.Context, s S) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for i := range s {
+			if !chanSend(ctx, out, s[i]) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ChanToSlice receives all values from the channel until it is closed. In case the context is done
+// first, returns the received values with the context error.
+func ChanToSlice[T any](ctx context.Context, ch <-chan T) ([]T, error) {
+	result := []T{}
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return result, nil
+			}
+			result = append(result, v)
+		case <-ctx.Done():
+			return result, ctx.Err() // nolint: wrapcheck
+		}
+	}
+}
+
+// ChanMerge merges values from multiple channels into one channel (fan-in).
+// The output channel is closed when all input channels are closed or when the context is done.
+func ChanMerge[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, ch := range chans {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for {
+				select {
+				case v, ok := <-ch:
+					if !ok || !chanSend(ctx, out, v) {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// chanDistribute receives values from the input channel and passes every value to `dispatch` together
+// with `n` output channels. The output channels are closed when the input channel is closed or
+// when the context is done.
+func chanDistribute[T any](
+	ctx context.Context,
+	ch <-chan T,
+	n int,
+	dispatch func(v T, outs []chan T) bool,
+) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok || !dispatch(v, outs) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return result
+}
+
+// ChanTee duplicates every value from the input channel to `n` output channels.
+// A value is sent to all the output channels before the next one is received, so a slow consumer
+// slows down all the others.
+func ChanTee[T any](ctx context.Context, ch <-chan T, n int) []<-chan T {
+	return chanDistribute(ctx, ch, n, func(v T, outs []chan T) bool {
+		for _, out := range outs {
+			if !chanSend(ctx, out, v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ChanFanOut distributes values from the input channel to `n` output channels in round-robin order
+func ChanFanOut[T any](ctx context.Context, ch <-chan T, n int) []<-chan T {
+	next := 0
+	return chanDistribute(ctx, ch, n, func(v T, outs []chan T) bool {
+		out := outs[next]
+		next = (next + 1) % len(outs)
+		return chanSend(ctx, out, v)
+	})
+}
+
+// ChanFanOutBy distributes values from the input channel to `n` output channels using a select function.
+// The value is sent to the output channel at index `selectFunc(v) % n`, so values having the same key
+// always go to the same channel.
+func ChanFanOutBy[T any](ctx context.Context, ch <-chan T, n int, selectFunc func(T) int) []<-chan T {
+	return chanDistribute(ctx, ch, n, func(v T, outs []chan T) bool {
+		index := selectFunc(v) % len(outs)
+		if index < 0 {
+			index += len(outs)
+		}
+		return chanSend(ctx, outs[index], v)
+	})
+}
+
+// ChanMap transforms values from the input channel using the map function
+func ChanMap[T any, U any](ctx context.Context, ch <-chan T, mapFunc func(T) U) <-chan U {
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok || !chanSend(ctx, out, mapFunc(v)) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ChanFilter passes only values satisfying the condition from the input channel
+func ChanFilter[T any](ctx context.Context, ch <-chan T, filterFunc func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					return
+				}
+				if filterFunc(v) && !chanSend(ctx, out, v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ChanBuffer groups values from the input channel into slices of at most `size` items.
+// A slice is emitted when it is full or when `maxWait` has elapsed since its first item was received
+// (pass 0 to emit by size only). The remaining items are emitted when the input channel is closed.
+func ChanBuffer[T any](ctx context.Context, ch <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size <= 0 {
+		size = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var buf []T
+		var timer *time.Timer
+		var timerChan <-chan time.Time
+		stopTimer := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, timerChan = nil, nil
+			}
+		}
+		defer stopTimer()
+
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					if len(buf) > 0 {
+						chanSend(ctx, out, buf)
+					}
+					return
+				}
+				buf = append(buf, v)
+				if len(buf) >= size {
+					stopTimer()
+					if !chanSend(ctx, out, buf) {
+						return
+					}
+					buf = nil
+				} else if timer == nil && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timerChan = timer.C
+				}
+			case <-timerChan:
+				timer, timerChan = nil, nil
+				if !chanSend(ctx, out, buf) {
+					return
+				}
+				buf = nil
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,165 @@
+package gofn
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SliceToChan_ChanToSlice(t *testing.T) {
+	ctx := context.Background()
+	s, err := ChanToSlice(ctx, SliceToChan(ctx, []int{1, 2, 3}))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	s, err = ChanToSlice(ctx, SliceToChan(ctx, []int{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, s)
+
+	// Context done
+	ctxCancel, cancel := context.WithCancel(ctx)
+	ch := SliceToChan(ctxCancel, []int{1, 2, 3})
+	assert.Equal(t, 1, <-ch)
+	cancel()
+	for range ch { //nolint:revive
+	}
+
+	ctxTimeout, cancel2 := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel2()
+	s, err = ChanToSlice(ctxTimeout, make(chan int))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []int{}, s)
+}
+
+func Test_ChanMerge(t *testing.T) {
+	ctx := context.Background()
+	s, err := ChanToSlice(ctx, ChanMerge(ctx,
+		SliceToChan(ctx, []int{1, 2, 3}),
+		SliceToChan(ctx, []int{4, 5}),
+		SliceToChan(ctx, []int{}),
+	))
+	assert.Nil(t, err)
+	sort.Ints(s)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s)
+
+	s, err = ChanToSlice(ctx, ChanMerge[int](ctx))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, s)
+
+	// Context canceled, the output is closed even when inputs are never closed
+	ctxCancel, cancel := context.WithCancel(ctx)
+	out := ChanMerge(ctxCancel, make(chan int), make(chan int))
+	cancel()
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func Test_ChanTee(t *testing.T) {
+	ctx := context.Background()
+	outs := ChanTee(ctx, SliceToChan(ctx, []int{1, 2, 3}), 3)
+	assert.Equal(t, 3, len(outs))
+
+	var wg sync.WaitGroup
+	results := make([][]int, 3)
+	for i := range outs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = ChanToSlice(ctx, outs[i])
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, [][]int{{1, 2, 3}, {1, 2, 3}, {1, 2, 3}}, results)
+
+	// Context canceled while nobody reads the outputs
+	ctxCancel, cancel := context.WithCancel(ctx)
+	outs = ChanTee(ctxCancel, SliceToChan(ctx, []int{1, 2, 3}), 2)
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	for _, out := range outs {
+		for range out { //nolint:revive
+		}
+	}
+}
+
+func Test_ChanFanOut(t *testing.T) {
+	ctx := context.Background()
+	outs := ChanFanOut(ctx, SliceToChan(ctx, []int{1, 2, 3, 4, 5, 6}), 3)
+
+	var wg sync.WaitGroup
+	results := make([][]int, 3)
+	for i := range outs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = ChanToSlice(ctx, outs[i])
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, [][]int{{1, 4}, {2, 5}, {3, 6}}, results)
+}
+
+func Test_ChanFanOutBy(t *testing.T) {
+	ctx := context.Background()
+	outs := ChanFanOutBy(ctx, SliceToChan(ctx, []int{1, 2, 3, 4, 5, 6, -1}), 2, func(v int) int { return v })
+
+	var wg sync.WaitGroup
+	results := make([][]int, 2)
+	for i := range outs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = ChanToSlice(ctx, outs[i])
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, [][]int{{2, 4, 6}, {1, 3, 5, -1}}, results)
+}
+
+func Test_ChanMap_ChanFilter(t *testing.T) {
+	ctx := context.Background()
+	in := SliceToChan(ctx, []int{1, 2, 3, 4, 5})
+	evens := ChanFilter(ctx, in, func(v int) bool { return v%2 == 0 })
+	doubled := ChanMap(ctx, evens, func(v int) int { return v * 2 })
+	s, err := ChanToSlice(ctx, doubled)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{4, 8}, s)
+
+	ctxCancel, cancel := context.WithCancel(ctx)
+	out1 := ChanMap(ctxCancel, make(chan int), func(v int) int { return v })
+	out2 := ChanFilter(ctxCancel, make(chan int), func(v int) bool { return true })
+	cancel()
+	_, ok := <-out1
+	assert.False(t, ok)
+	_, ok = <-out2
+	assert.False(t, ok)
+}
+
+func Test_ChanBuffer(t *testing.T) {
+	ctx := context.Background()
+	s, err := ChanToSlice(ctx, ChanBuffer(ctx, SliceToChan(ctx, []int{1, 2, 3, 4, 5}), 2, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, s)
+
+	// Emit by time
+	in := make(chan int)
+	out := ChanBuffer(ctx, in, 10, 20*time.Millisecond)
+	in <- 1
+	in <- 2
+	assert.Equal(t, []int{1, 2}, <-out)
+	in <- 3
+	close(in)
+	assert.Equal(t, []int{3}, <-out)
+	_, ok := <-out
+	assert.False(t, ok)
+
+	ctxCancel, cancel := context.WithCancel(ctx)
+	out = ChanBuffer(ctxCancel, make(chan int), 10, 0)
+	cancel()
+	_, ok = <-out
+	assert.False(t, ok)
+}
@@ -0,0 +1,311 @@
+package gofn
+
+import (
+	"sync"
+	"time"
+)
+
+type CircuitState int8
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+type CircuitBreakerConfig struct {
+	consecutiveFailures uint
+	failureRatio        float64
+	minRequests         uint
+	interval            time.Duration
+	openTimeout         time.Duration
+	halfOpenMaxProbes   uint
+	isFailure           func(error) bool
+	onStateChange       func(from, to CircuitState)
+}
+
+type CircuitBreakerOption func(*CircuitBreakerConfig)
+
+// CircuitBreakerConsecutiveFailures trips the circuit when the number of consecutive failures
+// reaches the threshold (default is 5, pass 0 to disable this rule)
+func CircuitBreakerConsecutiveFailures(threshold uint) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.consecutiveFailures = threshold
+	}
+}
+
+// CircuitBreakerFailureRatio trips the circuit when the ratio of failures reaches the threshold
+// and the number of completed requests is at least `minRequests`
+func CircuitBreakerFailureRatio(ratio float64, minRequests uint) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.failureRatio = ratio
+		config.minRequests = minRequests
+	}
+}
+
+// CircuitBreakerInterval sets the period to clear the counts while the circuit is closed
+// (default is 0, the counts are cleared only when the state changes)
+func CircuitBreakerInterval(interval time.Duration) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.interval = interval
+	}
+}
+
+// CircuitBreakerOpenTimeout sets the time the circuit stays open before switching to half-open (default is 60s)
+func CircuitBreakerOpenTimeout(timeout time.Duration) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.openTimeout = timeout
+	}
+}
+
+// CircuitBreakerHalfOpenMaxProbes sets the number of requests allowed while the circuit is half-open
+// (default is 1). The circuit is closed when all of them succeed.
+func CircuitBreakerHalfOpenMaxProbes(maxProbes uint) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.halfOpenMaxProbes = maxProbes
+	}
+}
+
+// CircuitBreakerIsFailure sets a function to check whether an error counts as a failure
+// (default is all non-nil errors)
+func CircuitBreakerIsFailure(isFailure func(error) bool) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.isFailure = isFailure
+	}
+}
+
+// CircuitBreakerOnStateChange sets a callback which is called when the state changes
+func CircuitBreakerOnStateChange(onStateChange func(from, to CircuitState)) CircuitBreakerOption {
+	return func(config *CircuitBreakerConfig) {
+		config.onStateChange = onStateChange
+	}
+}
+
+type circuitStateChange struct {
+	from, to CircuitState
+}
+
+// CircuitBreaker stops calling a failing dependency for a while to let it recover.
+// All methods are safe for concurrent use.
+type CircuitBreaker struct {
+	cfg                 *CircuitBreakerConfig
+	mu                  sync.Mutex
+	state               CircuitState
+	generation          uint64
+	requests            uint // completed requests of the current generation
+	failures            uint
+	consecutiveFailures uint
+	halfOpenInFlight    uint
+	halfOpenSuccesses   uint
+	openedAt            time.Time
+	countsExpiry        time.Time
+	changes             []circuitStateChange
+}
+
+// NewCircuitBreaker creates a new circuit breaker in closed state
+func NewCircuitBreaker(options ...CircuitBreakerOption) *CircuitBreaker {
+	cfg := &CircuitBreakerConfig{
+		consecutiveFailures: 5,                // nolint: mnd
+		openTimeout:         60 * time.Second, // nolint: mnd
+		halfOpenMaxProbes:   1,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	if cfg.halfOpenMaxProbes == 0 {
+		cfg.halfOpenMaxProbes = 1
+	}
+	cb := &CircuitBreaker{cfg: cfg}
+	cb.resetCounts(time.Now())
+	return cb
+}
+
+// State returns the current state of the circuit
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	state := cb.currentState(time.Now())
+	changes := cb.takeChanges()
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+	return state
+}
+
+// Allow checks whether a request can be made. If not, returns ErrCircuitOpen. Otherwise, returns
+// a function which must be called with the result of the request.
+func (cb *CircuitBreaker) Allow() (func(err error), error) {
+	cb.mu.Lock()
+	now := time.Now()
+	state := cb.currentState(now)
+	var err error
+	switch state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.halfOpenInFlight >= cb.cfg.halfOpenMaxProbes {
+			err = ErrCircuitOpen
+		} else {
+			cb.halfOpenInFlight++
+		}
+	case CircuitClosed:
+	}
+	generation := cb.generation
+	changes := cb.takeChanges()
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+	if err != nil {
+		return nil, err
+	}
+	return func(err error) { cb.record(generation, err) }, nil
+}
+
+// Execute executes the function if the circuit allows, returns ErrCircuitOpen otherwise
+func (cb *CircuitBreaker) Execute(fn func() error) (err error) {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			done(newPanicError(r, -1))
+			panic(r)
+		}
+	}()
+	err = fn()
+	done(err)
+	return err
+}
+
+func (cb *CircuitBreaker) record(generation uint64, err error) {
+	cb.mu.Lock()
+	now := time.Now()
+	state := cb.currentState(now)
+	// The result of a request made in a previous state is ignored
+	if generation == cb.generation {
+		// Only completed requests are counted, so in-flight requests don't dilute the failure ratio
+		cb.requests++
+		failed := err != nil
+		if failed && cb.cfg.isFailure != nil {
+			failed = cb.cfg.isFailure(err)
+		}
+		if failed {
+			cb.onFailure(state, now)
+		} else {
+			cb.onSuccess(state, now)
+		}
+	}
+	changes := cb.takeChanges()
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+}
+
+func (cb *CircuitBreaker) onSuccess(state CircuitState, now time.Time) {
+	switch state {
+	case CircuitClosed:
+		cb.consecutiveFailures = 0
+	case CircuitHalfOpen:
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses >= cb.cfg.halfOpenMaxProbes {
+			cb.setState(CircuitClosed, now)
+		}
+	case CircuitOpen:
+	}
+}
+
+func (cb *CircuitBreaker) onFailure(state CircuitState, now time.Time) {
+	switch state {
+	case CircuitClosed:
+		cb.failures++
+		cb.consecutiveFailures++
+		if cb.shouldTrip() {
+			cb.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		cb.setState(CircuitOpen, now)
+	case CircuitOpen:
+	}
+}
+
+func (cb *CircuitBreaker) shouldTrip() bool {
+	if cb.cfg.consecutiveFailures > 0 && cb.consecutiveFailures >= cb.cfg.consecutiveFailures {
+		return true
+	}
+	if cb.cfg.failureRatio > 0 && cb.requests > 0 && cb.requests >= cb.cfg.minRequests {
+		return float64(cb.failures)/float64(cb.requests) >= cb.cfg.failureRatio
+	}
+	return false
+}
+
+// currentState returns the state at the given time, must be called with the lock held
+func (cb *CircuitBreaker) currentState(now time.Time) CircuitState {
+	switch cb.state {
+	case CircuitClosed:
+		if !cb.countsExpiry.IsZero() && now.After(cb.countsExpiry) {
+			cb.resetCounts(now)
+		}
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) >= cb.cfg.openTimeout {
+			cb.setState(CircuitHalfOpen, now)
+		}
+	case CircuitHalfOpen:
+	}
+	return cb.state
+}
+
+// setState changes the state, must be called with the lock held
+func (cb *CircuitBreaker) setState(state CircuitState, now time.Time) {
+	if cb.state == state {
+		return
+	}
+	cb.changes = append(cb.changes, circuitStateChange{from: cb.state, to: state})
+	cb.state = state
+	if state == CircuitOpen {
+		cb.openedAt = now
+	}
+	cb.resetCounts(now)
+}
+
+// resetCounts starts a new generation of counts, must be called with the lock held
+func (cb *CircuitBreaker) resetCounts(now time.Time) {
+	cb.generation++
+	cb.requests = 0
+	cb.failures = 0
+	cb.consecutiveFailures = 0
+	cb.halfOpenInFlight = 0
+	cb.halfOpenSuccesses = 0
+	cb.countsExpiry = time.Time{}
+	if cb.state == CircuitClosed && cb.cfg.interval > 0 {
+		cb.countsExpiry = now.Add(cb.cfg.interval)
+	}
+}
+
+// takeChanges takes the pending state changes, must be called with the lock held
+func (cb *CircuitBreaker) takeChanges() []circuitStateChange {
+	changes := cb.changes
+	cb.changes = nil
+	return changes
+}
+
+// notify calls the state change callback outside the lock, so the callback can use the circuit breaker
+func (cb *CircuitBreaker) notify(changes []circuitStateChange) {
+	if cb.cfg.onStateChange == nil {
+		return
+	}
+	for _, change := range changes {
+		cb.cfg.onStateChange(change.from, change.to)
+	}
+}
@@ -0,0 +1,223 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_CircuitBreaker(t *testing.T) {
+	errTest := errors.New("test error")
+	fail := func() error { return errTest }
+	succeed := func() error { return nil }
+
+	t.Run("state string", func(t *testing.T) {
+		assert.Equal(t, "closed", CircuitClosed.String())
+		assert.Equal(t, "open", CircuitOpen.String())
+		assert.Equal(t, "half-open", CircuitHalfOpen.String())
+		assert.Equal(t, "unknown", CircuitState(100).String())
+	})
+
+	t.Run("trip by consecutive failures", func(t *testing.T) {
+		var mu sync.Mutex
+		var changes []string
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(3),
+			CircuitBreakerOpenTimeout(20*time.Millisecond),
+			CircuitBreakerOnStateChange(func(from, to CircuitState) {
+				mu.Lock()
+				changes = append(changes, from.String()+"->"+to.String())
+				mu.Unlock()
+			}))
+
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Nil(t, cb.Execute(succeed)) // resets consecutive failures
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitClosed, cb.State())
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitOpen, cb.State())
+		assert.ErrorIs(t, cb.Execute(succeed), ErrCircuitOpen)
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+		assert.Nil(t, cb.Execute(succeed))
+		assert.Equal(t, CircuitClosed, cb.State())
+
+		mu.Lock()
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, changes)
+		mu.Unlock()
+	})
+
+	t.Run("trip by failure ratio", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(0), CircuitBreakerFailureRatio(0.5, 4))
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Nil(t, cb.Execute(succeed))
+		assert.Equal(t, CircuitClosed, cb.State()) // not enough requests
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("trip by failure ratio with requests in flight", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(0), CircuitBreakerFailureRatio(0.5, 4))
+		release := make(chan struct{})
+		var started, wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			started.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = cb.Execute(func() error {
+					started.Done()
+					<-release
+					return nil
+				})
+			}()
+		}
+		started.Wait()
+
+		// In-flight requests don't count, 4 failures of 4 completed requests trip the circuit
+		for i := 0; i < 4; i++ {
+			assert.ErrorIs(t, cb.Execute(fail), errTest)
+		}
+		assert.Equal(t, CircuitOpen, cb.State())
+		close(release)
+		wg.Wait()
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("counts cleared by interval", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(2), CircuitBreakerInterval(20*time.Millisecond))
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		time.Sleep(30 * time.Millisecond)
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitClosed, cb.State())
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("half-open probes", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(1),
+			CircuitBreakerOpenTimeout(10*time.Millisecond), CircuitBreakerHalfOpenMaxProbes(2))
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		time.Sleep(20 * time.Millisecond)
+
+		done1, err := cb.Allow()
+		assert.Nil(t, err)
+		done2, err := cb.Allow()
+		assert.Nil(t, err)
+		_, err = cb.Allow()
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+
+		done1(nil)
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+		done2(nil)
+		assert.Equal(t, CircuitClosed, cb.State())
+
+		// Failure in half-open state opens the circuit again
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		time.Sleep(20 * time.Millisecond)
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("custom failure check and stale results", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(1),
+			CircuitBreakerIsFailure(func(err error) bool { return !errors.Is(err, context.Canceled) }))
+		assert.ErrorIs(t, cb.Execute(func() error { return context.Canceled }), context.Canceled)
+		assert.Equal(t, CircuitClosed, cb.State())
+
+		done, err := cb.Allow()
+		assert.Nil(t, err)
+		assert.ErrorIs(t, cb.Execute(fail), errTest)
+		assert.Equal(t, CircuitOpen, cb.State())
+		done(nil) // result of the request made in closed state is ignored
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("panic counts as failure", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(1))
+		assert.Panics(t, func() {
+			_ = cb.Execute(func() error { panic(errTest) })
+		})
+		assert.Equal(t, CircuitOpen, cb.State())
+	})
+}
+
+// nolint
+func Test_ExecRetryCircuitBreaker(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("retry loop stops when the circuit opens", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(3))
+		count := 0
+		err := ExecRetry(func() error {
+			count++
+			return errTest
+		}, 10, time.Millisecond, ExecRetryCircuitBreaker(cb))
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.ErrorIs(t, err, errTest)
+		assert.Equal(t, 3, count)
+
+		// Circuit is open, no attempt is made
+		v, err := ExecRetryCtx2(context.Background(), func() (int, error) {
+			count++
+			return 1, nil
+		}, 10, time.Millisecond, ExecRetryCircuitBreaker(cb))
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 0, v)
+		assert.Equal(t, 3, count)
+
+		// Attempts rejected by the circuit breaker are not counted as executed
+		giveUpAttempts := -1
+		err = ExecRetry(func() error {
+			count++
+			return nil
+		}, 10, time.Millisecond, ExecRetryCircuitBreaker(cb),
+			ExecRetryOnGiveUp(func(attempts int, lastErr error) { giveUpAttempts = attempts }))
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 0, giveUpAttempts)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("success through the circuit breaker", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(3))
+		count := 0
+		v1, v2, err := ExecRetry3(func() (int, string, error) {
+			count++
+			if count < 3 {
+				return 0, "", errTest
+			}
+			return 1, "a", nil
+		}, 10, time.Millisecond, ExecRetryCircuitBreaker(cb))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, v1)
+		assert.Equal(t, "a", v2)
+		assert.Equal(t, CircuitClosed, cb.State())
+	})
+
+	t.Run("panicking probe releases the half-open slot", func(t *testing.T) {
+		cb := NewCircuitBreaker(CircuitBreakerConsecutiveFailures(1), CircuitBreakerOpenTimeout(10*time.Millisecond))
+		assert.ErrorIs(t, cb.Execute(func() error { return errTest }), errTest)
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+
+		// The panic is recovered by the task execution and the probe counts as a failure
+		errMap := ExecTasksOpt(context.Background(), []func(ctx context.Context) error{
+			func(ctx context.Context) error { panic(errTest) },
+		}, ExecTasksRetry(0, 0, ExecRetryCircuitBreaker(cb)))
+		assert.ErrorIs(t, errMap[0], ErrPanic)
+		assert.Equal(t, CircuitOpen, cb.State())
+
+		// The circuit recovers after the open timeout
+		time.Sleep(20 * time.Millisecond)
+		assert.Nil(t, ExecRetry(func() error { return nil }, 0, 0, ExecRetryCircuitBreaker(cb)))
+		assert.Equal(t, CircuitClosed, cb.State())
+	})
+}
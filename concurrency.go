@@ -2,10 +2,181 @@ package gofn
 
 import (
 	"context"
-	"fmt"
-	"sync/atomic"
+	"sync"
+	"time"
 )
 
+type ExecTasksConfig struct {
+	maxConcurrentTasks uint
+	stopOnError        bool
+	waitOnStop         bool
+	rateLimiter        *RateLimiter
+	taskTimeout        time.Duration
+	retrier            *Retrier
+	onStart            func(index int)
+	onFinish           func(index int, duration time.Duration, err error)
+	onProgress         func(done, total int)
+	onComplete         func(summary *ExecTasksSummary)
+	weightFunc         func(index int) int64
+	keyFunc            func(index int) any
+}
+
+type ExecTasksOption func(*ExecTasksConfig)
+
+// ExecTasksMaxConcurrency sets the maximum number of tasks running at the same time (0 means no limit)
+func ExecTasksMaxConcurrency(maxConcurrentTasks uint) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.maxConcurrentTasks = maxConcurrentTasks
+	}
+}
+
+// ExecTasksStopOnError sets whether to stop the execution on the first failure (default is true).
+// When stopped, the context passed to the tasks is canceled and the tasks not started yet are skipped.
+func ExecTasksStopOnError(stopOnError bool) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.stopOnError = stopOnError
+	}
+}
+
+// ExecTasksWaitOnStop sets whether to wait for all started tasks to exit before returning
+// when the execution is stopped on error. This guarantees no task is still running after the call.
+func ExecTasksWaitOnStop(wait bool) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.waitOnStop = wait
+	}
+}
+
+// ExecTasksRateLimit limits the start rate of the tasks using the given rate limiter.
+// The limiter can be shared between multiple executions to apply a global rate.
+func ExecTasksRateLimit(rateLimiter *RateLimiter) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.rateLimiter = rateLimiter
+	}
+}
+
+// ExecTasksTimeout sets the timeout of every task. When retry is set, this is the timeout of every attempt
+// unless ExecRetryAttemptTimeout() is given, and an attempt timing out is always retried.
+func ExecTasksTimeout(timeout time.Duration) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.taskTimeout = timeout
+	}
+}
+
+// ExecTasksRetry retries every failed task using the same params as ExecRetryCtxFunc(), every attempt
+// receives its own context, so ExecRetryAttemptTimeout() applies.
+// Use ExecRetryIfErrorIs() or ExecRetryCheck() to retry only on specific errors.
+func ExecTasksRetry(maxRetries int, delay time.Duration, options ...ExecRetryOption) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.retrier = NewRetrier(maxRetries, delay, options...)
+	}
+}
+
+// ExecTasksWeight sets the weight of every task. A task occupies as many slots of `maxConcurrentTasks`
+// as its weight (a weight bigger than maxConcurrentTasks is capped, a weight less than 1 is considered 1).
+// Slots are handed out in the task order.
+func ExecTasksWeight(weightFunc func(index int) int64) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.weightFunc = weightFunc
+	}
+}
+
+// ExecTasksKey sets the key of every task. Tasks having the same key are executed one at a time,
+// tasks having different keys run concurrently within `maxConcurrentTasks`.
+// NOTE: a task waiting for its key occupies a slot of `maxConcurrentTasks`.
+func ExecTasksKey[K comparable](keyFunc func(index int) K) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.keyFunc = func(index int) any { return keyFunc(index) }
+	}
+}
+
+// ExecTasksOnStart sets a callback which is called right before a task starts.
+// NOTE: the callback is called from the task goroutines, it must be safe for concurrent use.
+func ExecTasksOnStart(onStart func(index int)) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.onStart = onStart
+	}
+}
+
+// ExecTasksOnFinish sets a callback which is called when a task finishes.
+// Calls of the callback are serialized, so no synchronization is needed.
+func ExecTasksOnFinish(onFinish func(index int, duration time.Duration, err error)) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.onFinish = onFinish
+	}
+}
+
+// ExecTasksOnProgress sets a callback which is called every time a task finishes with the number
+// of finished tasks and the total number of tasks. Calls of the callback are serialized.
+func ExecTasksOnProgress(onProgress func(done, total int)) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.onProgress = onProgress
+	}
+}
+
+// ExecTasksOnComplete sets a callback which is called with the summary of the whole execution
+// right before the execution function returns
+func ExecTasksOnComplete(onComplete func(summary *ExecTasksSummary)) ExecTasksOption {
+	return func(config *ExecTasksConfig) {
+		config.onComplete = onComplete
+	}
+}
+
+// ExecTasksSummary summary of an execution of tasks.
+// NOTE: when the execution stops on error without waiting, tasks which are still running
+// when the execution returns are counted as started but not finished.
+type ExecTasksSummary struct {
+	TaskCount     int
+	StartedCount  int
+	FinishedCount int
+	FailedCount   int
+	// Duration of the whole execution
+	Duration time.Duration
+	// Durations of the finished tasks by task index, zero for unfinished ones
+	TaskDurations     []time.Duration
+	MinTaskDuration   time.Duration
+	MaxTaskDuration   time.Duration
+	TotalTaskDuration time.Duration
+}
+
+// AvgTaskDuration calculates the average duration of the finished tasks
+func (s *ExecTasksSummary) AvgTaskDuration() time.Duration {
+	if s.FinishedCount == 0 {
+		return 0
+	}
+	return s.TotalTaskDuration / time.Duration(s.FinishedCount)
+}
+
+func (s *ExecTasksSummary) addTask(index int, duration time.Duration, err error) {
+	if s.FinishedCount == 0 || duration < s.MinTaskDuration {
+		s.MinTaskDuration = duration
+	}
+	if duration > s.MaxTaskDuration {
+		s.MaxTaskDuration = duration
+	}
+	s.FinishedCount++
+	if err != nil {
+		s.FailedCount++
+	}
+	s.TaskDurations[index] = duration
+	s.TotalTaskDuration += duration
+}
+
+func newExecTasksConfig(options []ExecTasksOption) *ExecTasksConfig {
+	cfg := &ExecTasksConfig{
+		stopOnError: true,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	// The task timeout becomes the attempt timeout, so a timed-out attempt is retried
+	if cfg.retrier != nil && cfg.taskTimeout > 0 && cfg.retrier.cfg.attemptTimeout <= 0 {
+		retrier := *cfg.retrier
+		retrier.cfg.attemptTimeout = cfg.taskTimeout
+		cfg.retrier = &retrier
+	}
+	return cfg
+}
+
 // ExecTasks calls ExecTasksEx with stopOnError is true
 func ExecTasks(
 	ctx context.Context,
@@ -22,82 +193,282 @@ func ExecTasks(
 // ExecTasksEx execute multiple tasks concurrently using Go routines
 // maxConcurrentTasks behaves similarly as `pool size`, pass 0 to set no limit.
 // In case you want to cancel the execution, use context.WithTimeout() or context.WithCancel().
-// nolint: gocognit
+// When stopOnError is true, the context passed to the tasks is canceled on the first failure,
+// and the function returns without waiting for the in-flight tasks (use ExecTasksOpt() with
+// ExecTasksWaitOnStop() to wait for them).
 func ExecTasksEx(
 	ctx context.Context,
 	maxConcurrentTasks uint,
 	stopOnError bool,
 	tasks ...func(ctx context.Context) error,
 ) map[int]error {
-	taskCount := len(tasks)
-	if taskCount == 0 {
-		return nil
+	cfg := &ExecTasksConfig{maxConcurrentTasks: maxConcurrentTasks, stopOnError: stopOnError}
+	_, errMap := execTasks(ctx, cfg, toResultTasks(tasks))
+	return errMap
+}
+
+// toResultTasks converts tasks to the form accepted by execTasks
+func toResultTasks(tasks []func(ctx context.Context) error) []func(ctx context.Context) (struct{}, error) {
+	resultTasks := make([]func(ctx context.Context) (struct{}, error), len(tasks))
+	for i := range tasks {
+		task := tasks[i]
+		resultTasks[i] = func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, task(ctx)
+		}
 	}
+	return resultTasks
+}
+
+type execTaskResult[R any] struct {
+	Index    int
+	Result   R
+	Error    error
+	Stopper  bool
+	Duration time.Duration
+}
+
+// execTasksTracker tracks the finished tasks of an execution, calls the hooks and builds the summary
+type execTasksTracker struct {
+	cfg       *ExecTasksConfig
+	taskCount int
+	doneCount int
+	startTime time.Time
+	summary   *ExecTasksSummary
+}
 
-	type execTaskResult struct {
-		Index int
-		Error error
+func newExecTasksTracker(cfg *ExecTasksConfig, taskCount int) *execTasksTracker {
+	t := &execTasksTracker{cfg: cfg, taskCount: taskCount, startTime: time.Now()}
+	if cfg.onComplete != nil {
+		t.summary = &ExecTasksSummary{
+			TaskCount:     taskCount,
+			TaskDurations: make([]time.Duration, taskCount),
+		}
+	}
+	return t
+}
+
+func (t *execTasksTracker) taskDone(index int, duration time.Duration, err error) {
+	t.doneCount++
+	if t.summary != nil {
+		t.summary.addTask(index, duration, err)
+	}
+	if t.cfg.onFinish != nil {
+		t.cfg.onFinish(index, duration, err)
 	}
+	if t.cfg.onProgress != nil {
+		t.cfg.onProgress(t.doneCount, t.taskCount)
+	}
+}
 
-	stopped := &atomic.Value{} // NOTE: Go 1.18 has no atomic.Bool type
-	resultChan := make(chan *execTaskResult, taskCount)
-	var limiterChan chan struct{}
-	if maxConcurrentTasks != 0 && maxConcurrentTasks < uint(taskCount) {
-		limiterChan = make(chan struct{}, maxConcurrentTasks)
-	} else {
-		maxConcurrentTasks = 0
+func (t *execTasksTracker) complete(startedCount int) {
+	if t.summary == nil {
+		return
 	}
+	t.summary.StartedCount = startedCount
+	t.summary.Duration = time.Since(t.startTime)
+	t.cfg.onComplete(t.summary)
+}
+
+// execTasksLauncher launches the tasks of an execution
+type execTasksLauncher[R any] struct {
+	cfg        *ExecTasksConfig
+	tasks      []func(ctx context.Context) (R, error)
+	ctx        context.Context
+	launchCtx  context.Context
+	stop       func() bool
+	limiter    *Semaphore
+	keyedMutex *KeyedMutex[any]
+	resultChan chan *execTaskResult[R]
+}
 
-	for i := 0; i < taskCount; i++ {
+// launch launches the tasks in order until all are launched or the execution is stopped,
+// returns the number of launched tasks
+func (l *execTasksLauncher[R]) launch() int {
+	maxConcurrentTasks := int64(l.cfg.maxConcurrentTasks)
+	for i := range l.tasks {
 		// In case we set pool size, when out of slot, this will wait until one to be available again
-		if maxConcurrentTasks != 0 {
-			limiterChan <- struct{}{}
+		weight := int64(1)
+		if l.limiter != nil {
+			if l.cfg.weightFunc != nil {
+				weight = Clamp(l.cfg.weightFunc(i), 1, maxConcurrentTasks)
+			}
+			if l.limiter.Acquire(l.launchCtx, weight) != nil {
+				return i
+			}
+		}
+		if l.launchCtx.Err() != nil {
+			if l.limiter != nil {
+				l.limiter.Release(weight)
+			}
+			return i
+		}
+		go l.run(i, weight)
+	}
+	return len(l.tasks)
+}
+
+// run executes a task and sends its result
+func (l *execTasksLauncher[R]) run(i int, weight int64) {
+	res := &execTaskResult[R]{Index: i}
+	var taskStartTime time.Time
+	defer func() {
+		if !taskStartTime.IsZero() {
+			res.Duration = time.Since(taskStartTime)
+		}
+		// In case we set pool size, release the slot when the task ends
+		if l.limiter != nil {
+			l.limiter.Release(weight)
 		}
 
-		go func(i int, task func(ctx context.Context) error) {
-			defer func() {
-				// In case we set pool size, release the slot when the task ends
-				if maxConcurrentTasks != 0 {
-					<-limiterChan
-				}
+		if r := recover(); r != nil {
+			res.Error = newPanicError(r, i)
+		}
+		if res.Error != nil && l.cfg.stopOnError {
+			res.Stopper = l.stop()
+		}
+		l.resultChan <- res
+	}()
 
-				r := recover()
-				if r != nil && stopped.Load() == nil {
-					resultChan <- &execTaskResult{Index: i, Error: fmt.Errorf("%w: %v", ErrPanic, r)}
-				}
-			}()
+	ctx, cfg := l.ctx, l.cfg
+	if err := ctx.Err(); err != nil {
+		res.Error = err
+		return
+	}
+	if l.keyedMutex != nil {
+		key := cfg.keyFunc(i)
+		if err := l.keyedMutex.LockCtx(ctx, key); err != nil {
+			res.Error = err
+			return
+		}
+		defer l.keyedMutex.Unlock(key)
+	}
+	if cfg.rateLimiter != nil {
+		if err := cfg.rateLimiter.Wait(ctx); err != nil {
+			res.Error = err
+			return
+		}
+	}
+	if cfg.onStart != nil {
+		cfg.onStart(i)
+	}
+	taskStartTime = time.Now()
+	res.Result, res.Error = execTask(ctx, cfg, l.tasks[i])
+}
 
-			if stopOnError && stopped.Load() != nil {
-				return
-			}
+// execTasks executes the tasks concurrently and collects their results in input order.
+// Results are only written by the calling goroutine, so tasks which are still running
+// after the function returns never touch the returned slice. Results are collected while
+// the tasks are being launched, so the hooks are called as soon as the tasks finish.
+// nolint: gocognit
+func execTasks[R any](
+	ctx context.Context,
+	cfg *ExecTasksConfig,
+	tasks []func(ctx context.Context) (R, error),
+) ([]R, map[int]error) {
+	taskCount := len(tasks)
+	if taskCount == 0 {
+		return nil, nil
+	}
+	tracker := newExecTasksTracker(cfg, taskCount)
 
-			if err := ctx.Err(); err != nil {
-				resultChan <- &execTaskResult{Index: i, Error: err}
-				return
-			}
+	// Tasks get a derived context which is canceled on the first failure (when stopOnError is set)
+	// or when this function returns, so tasks still in-flight are told to stop
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-			err := task(ctx)
-			if err != nil {
-				resultChan <- &execTaskResult{Index: i, Error: err}
-			} else {
-				resultChan <- nil
-			}
-		}(i, tasks[i])
+	// Launching tasks is stopped on error only, tasks are still launched when the parent context is done
+	// so that they can report the context error
+	launchCtx, launchCancel := context.WithCancel(context.Background())
+	defer launchCancel()
+
+	var stopOnce sync.Once
+	l := &execTasksLauncher[R]{
+		cfg:       cfg,
+		tasks:     tasks,
+		ctx:       ctx,
+		launchCtx: launchCtx,
+		// stop returns true only for the first caller, the one whose error is reported
+		stop: func() (first bool) {
+			stopOnce.Do(func() {
+				first = true
+				launchCancel()
+				cancel()
+			})
+			return first
+		},
+		resultChan: make(chan *execTaskResult[R], taskCount),
+	}
+	maxConcurrentTasks := int64(cfg.maxConcurrentTasks)
+	if maxConcurrentTasks != 0 && (maxConcurrentTasks < int64(taskCount) || cfg.weightFunc != nil) {
+		l.limiter = NewSemaphore(maxConcurrentTasks)
 	}
+	if cfg.keyFunc != nil {
+		l.keyedMutex = &KeyedMutex[any]{}
+	}
+
+	launchDone := make(chan int, 1)
+	go func() {
+		launchDone <- l.launch()
+	}()
 
+	results := make([]R, taskCount)
 	errResult := map[int]error{}
-	for i := 0; i < taskCount; i++ {
-		res := <-resultChan
-		if res == nil {
+	startedCount := -1
+	stopped := false
+	for startedCount < 0 || tracker.doneCount < startedCount {
+		var res *execTaskResult[R]
+		select {
+		case startedCount = <-launchDone:
+			continue
+		case res = <-l.resultChan:
+		}
+		tracker.taskDone(res.Index, res.Duration, res.Error)
+		if res.Error == nil {
+			results[res.Index] = res.Result
+			continue
+		}
+		if !cfg.stopOnError {
+			errResult[res.Index] = res.Error
+			continue
+		}
+		// Only the error which stops the execution is reported, other errors are
+		// likely caused by the cancellation of the context
+		if !res.Stopper || stopped {
 			continue
 		}
 		errResult[res.Index] = res.Error
-		if stopOnError {
-			stopped.Store(true)
+		stopped = true
+		if !cfg.waitOnStop {
 			break
 		}
 	}
-	return errResult
+
+	if startedCount < 0 {
+		// The launching is stopped, it returns quickly
+		startedCount = <-launchDone
+	}
+	tracker.complete(startedCount)
+	return results, errResult
+}
+
+// execTask executes a task applying the timeout and retry settings.
+// With retry, the retrier applies the attempt timeout to the context passed to the task.
+func execTask[R any](
+	ctx context.Context,
+	cfg *ExecTasksConfig,
+	task func(ctx context.Context) (R, error),
+) (R, error) {
+	if cfg.retrier != nil {
+		return RetrierDoAttempt(ctx, cfg.retrier, func(ctx context.Context, _ RetryAttempt) (R, error) {
+			return task(ctx)
+		})
+	}
+	if cfg.taskTimeout <= 0 {
+		return task(ctx)
+	}
+	taskCtx, cancel := context.WithTimeout(ctx, cfg.taskTimeout)
+	defer cancel()
+	return task(taskCtx)
 }
 
 // ExecTaskFunc executes a function on every target objects
@@ -131,3 +502,83 @@ func ExecTaskFuncEx[T any](
 	}
 	return ExecTasksEx(ctx, maxConcurrentTasks, stopOnError, tasks...)
 }
+
+// ExecTaskFuncResults calls ExecTaskFuncResultsEx with stopOnError is true
+func ExecTaskFuncResults[T any, R any](
+	ctx context.Context,
+	maxConcurrentTasks uint,
+	taskFunc func(ctx context.Context, obj T) (R, error),
+	targetObjects ...T,
+) ([]R, error) {
+	results, errMap := ExecTaskFuncResultsEx(ctx, maxConcurrentTasks, true, taskFunc, targetObjects...)
+	for _, v := range errMap {
+		return results, v
+	}
+	return results, nil
+}
+
+// ExecTaskFuncResultsEx executes a function on every target objects and collects the results.
+// The result slice has the same order as the target objects. In case a task fails, its result
+// is left as zero value and the error is put in the returned map at the task index.
+func ExecTaskFuncResultsEx[T any, R any](
+	ctx context.Context,
+	maxConcurrentTasks uint,
+	stopOnError bool,
+	taskFunc func(ctx context.Context, obj T) (R, error),
+	targetObjects ...T,
+) ([]R, map[int]error) {
+	tasks := make([]func(ctx context.Context) (R, error), len(targetObjects))
+	for i := range targetObjects {
+		obj := targetObjects[i]
+		tasks[i] = func(ctx context.Context) (R, error) {
+			return taskFunc(ctx, obj)
+		}
+	}
+	cfg := &ExecTasksConfig{maxConcurrentTasks: maxConcurrentTasks, stopOnError: stopOnError}
+	return execTasks(ctx, cfg, tasks)
+}
+
+// ExecTasksOpt executes multiple tasks concurrently with options.
+// By default, there is no concurrency limit and the execution stops on the first error.
+func ExecTasksOpt(
+	ctx context.Context,
+	tasks []func(ctx context.Context) error,
+	options ...ExecTasksOption,
+) map[int]error {
+	_, errMap := execTasks(ctx, newExecTasksConfig(options), toResultTasks(tasks))
+	return errMap
+}
+
+// ExecTaskFuncOpt executes a function on every target objects with options
+func ExecTaskFuncOpt[T any](
+	ctx context.Context,
+	taskFunc func(ctx context.Context, obj T) error,
+	targetObjects []T,
+	options ...ExecTasksOption,
+) map[int]error {
+	tasks := make([]func(ctx context.Context) error, len(targetObjects))
+	for i := range targetObjects {
+		obj := targetObjects[i]
+		tasks[i] = func(ctx context.Context) error {
+			return taskFunc(ctx, obj)
+		}
+	}
+	return ExecTasksOpt(ctx, tasks, options...)
+}
+
+// ExecTaskFuncResultsOpt executes a function on every target objects with options and collects the results
+func ExecTaskFuncResultsOpt[T any, R any](
+	ctx context.Context,
+	taskFunc func(ctx context.Context, obj T) (R, error),
+	targetObjects []T,
+	options ...ExecTasksOption,
+) ([]R, map[int]error) {
+	tasks := make([]func(ctx context.Context) (R, error), len(targetObjects))
+	for i := range targetObjects {
+		obj := targetObjects[i]
+		tasks[i] = func(ctx context.Context) (R, error) {
+			return taskFunc(ctx, obj)
+		}
+	}
+	return execTasks(ctx, newExecTasksConfig(options), tasks)
+}
@@ -0,0 +1,128 @@
+package gofn
+
+import (
+	"context"
+)
+
+// TaskResult result of a task sent by the streaming execution functions
+type TaskResult[R any] struct {
+	Index  int
+	Result R
+	Err    error
+}
+
+// ExecTaskFuncStream executes a function on every target objects concurrently and sends the results
+// to the returned channel in the order of the target objects. A failed task doesn't stop the execution,
+// its error is sent along with its result.
+// bufferSize limits the number of results being computed or waiting to be sent, so a slow task holds at
+// most bufferSize results in memory (pass 0 to use maxConcurrentTasks as the buffer size).
+// maxConcurrentTasks limits the number of running tasks, pass 0 to set no limit.
+// The channel is closed when all results are sent or when the context is done. A consumer which stops
+// reading early must cancel the context to release the execution.
+func ExecTaskFuncStream[T any, R any](
+	ctx context.Context,
+	maxConcurrentTasks uint,
+	bufferSize uint,
+	taskFunc func(ctx context.Context, obj T) (R, error),
+	targetObjects ...T,
+) <-chan TaskResult[R] {
+	return execTaskFuncStream(ctx, maxConcurrentTasks, bufferSize, true, taskFunc, targetObjects)
+}
+
+// ExecTaskFuncStreamUnordered executes a function on every target objects concurrently and sends the
+// results to the returned channel as soon as every task finishes. See ExecTaskFuncStream() for details.
+func ExecTaskFuncStreamUnordered[T any, R any](
+	ctx context.Context,
+	maxConcurrentTasks uint,
+	taskFunc func(ctx context.Context, obj T) (R, error),
+	targetObjects ...T,
+) <-chan TaskResult[R] {
+	return execTaskFuncStream(ctx, maxConcurrentTasks, maxConcurrentTasks, false, taskFunc, targetObjects)
+}
+
+// nolint: gocognit
+func execTaskFuncStream[T any, R any](
+	ctx context.Context,
+	maxConcurrentTasks uint,
+	bufferSize uint,
+	ordered bool,
+	taskFunc func(ctx context.Context, obj T) (R, error),
+	targetObjects []T,
+) <-chan TaskResult[R] {
+	taskCount := len(targetObjects)
+	window := taskCount
+	if bufferSize == 0 {
+		bufferSize = maxConcurrentTasks
+	}
+	if bufferSize > 0 && bufferSize < uint(taskCount) {
+		window = int(bufferSize)
+	}
+	if maxConcurrentTasks == 0 || maxConcurrentTasks > uint(window) {
+		maxConcurrentTasks = uint(window)
+	}
+
+	out := make(chan TaskResult[R])
+	go func() {
+		defer close(out)
+		// Buffered to the window size, so the tasks never block on sending their results
+		resultChan := make(chan TaskResult[R], window)
+		pending := make(map[int]TaskResult[R], window)
+		completed := make([]TaskResult[R], 0, window)
+		launched, sent, running := 0, 0, 0
+
+		for sent < taskCount {
+			for launched < taskCount && launched-sent < window && uint(running) < maxConcurrentTasks {
+				if ctx.Err() != nil {
+					return
+				}
+				i := launched
+				obj := targetObjects[i]
+				launched++
+				running++
+				go func() {
+					res := TaskResult[R]{Index: i}
+					defer func() {
+						if r := recover(); r != nil {
+							res.Err = newPanicError(r, i)
+						}
+						resultChan <- res
+					}()
+					res.Result, res.Err = taskFunc(ctx, obj)
+				}()
+			}
+
+			// Send the results which are ready
+			if ordered {
+				if res, ok := pending[sent]; ok {
+					delete(pending, sent)
+					if !chanSend(ctx, out, res) {
+						return
+					}
+					sent++
+					continue
+				}
+			} else if len(completed) > 0 {
+				res := completed[0]
+				completed = completed[1:]
+				if !chanSend(ctx, out, res) {
+					return
+				}
+				sent++
+				continue
+			}
+
+			select {
+			case res := <-resultChan:
+				running--
+				if ordered {
+					pending[res.Index] = res
+				} else {
+					completed = append(completed, res)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
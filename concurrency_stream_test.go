@@ -0,0 +1,112 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_ExecTaskFuncStream(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("ordered results", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		ch := ExecTaskFuncStream(context.Background(), 3, 4, func(ctx context.Context, v int) (int, error) {
+			n := running.Add(1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			defer running.Add(-1)
+			// Later items finish first
+			time.Sleep(time.Duration(10-v) * time.Millisecond)
+			if v == 5 {
+				return 0, errTest
+			}
+			if v == 7 {
+				panic("boom")
+			}
+			return v * 10, nil
+		}, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+		var indexes []int
+		for res := range ch {
+			indexes = append(indexes, res.Index)
+			switch res.Index {
+			case 5:
+				assert.ErrorIs(t, res.Err, errTest)
+			case 7:
+				assert.ErrorIs(t, res.Err, ErrPanic)
+			default:
+				assert.Nil(t, res.Err)
+				assert.Equal(t, res.Index*10, res.Result)
+			}
+		}
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, indexes)
+		assert.True(t, maxRunning.Load() <= 3)
+	})
+
+	t.Run("bounded buffer", func(t *testing.T) {
+		var started atomic.Int32
+		release := make(chan struct{})
+		ch := ExecTaskFuncStream(context.Background(), 0, 3, func(ctx context.Context, v int) (int, error) {
+			started.Add(1)
+			if v == 0 {
+				<-release
+			}
+			return v, nil
+		}, 0, 1, 2, 3, 4, 5)
+
+		time.Sleep(20 * time.Millisecond)
+		// The first task blocks, only the first 3 tasks can start
+		assert.Equal(t, int32(3), started.Load())
+		close(release)
+		result, err := ChanToSlice(context.Background(), ch)
+		assert.Nil(t, err)
+		assert.Equal(t, 6, len(result))
+		assert.Equal(t, int32(6), started.Load())
+	})
+
+	t.Run("unordered results", func(t *testing.T) {
+		ch := ExecTaskFuncStreamUnordered(context.Background(), 0, func(ctx context.Context, v int) (int, error) {
+			time.Sleep(time.Duration(v) * 10 * time.Millisecond)
+			return v, nil
+		}, 3, 1, 2)
+
+		var results []int
+		for res := range ch {
+			assert.Nil(t, res.Err)
+			results = append(results, res.Result)
+		}
+		assert.Equal(t, []int{1, 2, 3}, results)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var started atomic.Int32
+		ch := ExecTaskFuncStream(ctx, 2, 0, func(ctx context.Context, v int) (int, error) {
+			started.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			return v, nil
+		}, 1, 2, 3, 4, 5, 6, 7, 8)
+
+		res := <-ch
+		assert.Equal(t, 1, res.Result)
+		cancel()
+		for range ch {
+		}
+		assert.True(t, started.Load() < 8)
+	})
+
+	t.Run("no tasks", func(t *testing.T) {
+		ch := ExecTaskFuncStream(context.Background(), 0, 0, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		})
+		_, ok := <-ch
+		assert.False(t, ok)
+	})
+}
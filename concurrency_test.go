@@ -3,8 +3,10 @@ package gofn
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -265,3 +267,385 @@ func Test_ExecTaskFunc(t *testing.T) {
 		assert.True(t, ContentEqual([]int{1, 3, 5}, data.odds))
 	})
 }
+
+// nolint
+func Test_ExecTaskFuncResults(t *testing.T) {
+	errTest := errors.New("test error")
+
+	taskFunc := func(ctx context.Context, v int) (string, error) {
+		if v > 10 {
+			return "", errTest
+		}
+		if v < 0 {
+			panic(errTest)
+		}
+		time.Sleep(time.Duration(10+rand.Intn(50)) * time.Millisecond)
+		return fmt.Sprintf("%d", v*2), nil
+	}
+
+	t.Run("no tasks passed", func(t *testing.T) {
+		results, err := ExecTaskFuncResults(context.Background(), 0, taskFunc)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(results))
+	})
+
+	t.Run("no pool size, success", func(t *testing.T) {
+		results, err := ExecTaskFuncResults(context.Background(), 0, taskFunc, 1, 2, 3, 4, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2", "4", "6", "8", "10"}, results)
+	})
+
+	t.Run("pool size = 2, success", func(t *testing.T) {
+		results, errMap := ExecTaskFuncResultsEx(context.Background(), 2, false, taskFunc, 5, 4, 3, 2, 1)
+		assert.Equal(t, 0, len(errMap))
+		assert.Equal(t, []string{"10", "8", "6", "4", "2"}, results)
+	})
+
+	t.Run("no stop on error, failure", func(t *testing.T) {
+		results, errMap := ExecTaskFuncResultsEx(context.Background(), 0, false, taskFunc, 1, 11, 3, -1, 5)
+		assert.Equal(t, 2, len(errMap))
+		assert.ErrorIs(t, errMap[1], errTest)
+		assert.ErrorIs(t, errMap[3], ErrPanic)
+		assert.ErrorIs(t, errMap[3], errTest)
+		assert.Equal(t, []string{"2", "", "6", "", "10"}, results)
+
+		var panicErr *PanicError
+		assert.True(t, errors.As(errMap[3], &panicErr))
+		assert.Equal(t, 3, panicErr.TaskIndex)
+		assert.Contains(t, string(panicErr.Stack), "Test_ExecTaskFuncResults")
+	})
+
+	t.Run("stop on error, failure", func(t *testing.T) {
+		_, err := ExecTaskFuncResults(context.Background(), 0, taskFunc, 1, 2, 11, 3)
+		assert.ErrorIs(t, err, errTest)
+	})
+}
+
+// nolint
+func Test_ExecTasksOpt(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("no tasks passed", func(t *testing.T) {
+		errMap := ExecTasksOpt(context.Background(), nil)
+		assert.Equal(t, 0, len(errMap))
+	})
+
+	t.Run("in-flight tasks are canceled on error", func(t *testing.T) {
+		var canceledCount atomic.Int32
+		slowTask := func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				canceledCount.Add(1)
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		}
+		failTask := func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return errTest
+		}
+
+		start := time.Now()
+		errMap := ExecTasksOpt(context.Background(),
+			[]func(ctx context.Context) error{slowTask, slowTask, failTask},
+			ExecTasksWaitOnStop(true))
+		assert.True(t, time.Since(start) < time.Second)
+		assert.Equal(t, 1, len(errMap))
+		assert.ErrorIs(t, errMap[2], errTest)
+		// All started tasks have exited as we wait for them
+		assert.Equal(t, int32(2), canceledCount.Load())
+	})
+
+	t.Run("tasks not started yet are skipped on error", func(t *testing.T) {
+		var startedCount atomic.Int32
+		task := func(ctx context.Context) error {
+			startedCount.Add(1)
+			return errTest
+		}
+
+		errMap := ExecTasksOpt(context.Background(),
+			[]func(ctx context.Context) error{task, task, task, task, task},
+			ExecTasksMaxConcurrency(1), ExecTasksWaitOnStop(true))
+		assert.Equal(t, 1, len(errMap))
+		assert.ErrorIs(t, errMap[0], errTest)
+		assert.Equal(t, int32(1), startedCount.Load())
+	})
+
+	t.Run("panic cancels in-flight tasks", func(t *testing.T) {
+		slowTask := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		panicTask := func(ctx context.Context) error {
+			panic(errTest)
+		}
+
+		errMap := ExecTasksOpt(context.Background(),
+			[]func(ctx context.Context) error{slowTask, panicTask}, ExecTasksWaitOnStop(true))
+		assert.Equal(t, 1, len(errMap))
+		assert.ErrorIs(t, errMap[1], ErrPanic)
+	})
+
+	t.Run("no stop on error", func(t *testing.T) {
+		task := func(ctx context.Context) error {
+			return errTest
+		}
+		errMap := ExecTaskFuncOpt(context.Background(), func(ctx context.Context, v int) error {
+			return task(ctx)
+		}, []int{1, 2, 3}, ExecTasksStopOnError(false), ExecTasksMaxConcurrency(2))
+		assert.Equal(t, 3, len(errMap))
+	})
+
+	t.Run("results with options", func(t *testing.T) {
+		results, errMap := ExecTaskFuncResultsOpt(context.Background(), func(ctx context.Context, v int) (int, error) {
+			return v * v, nil
+		}, []int{1, 2, 3}, ExecTasksMaxConcurrency(2))
+		assert.Equal(t, 0, len(errMap))
+		assert.Equal(t, []int{1, 4, 9}, results)
+	})
+}
+
+// nolint
+func Test_ExecTasksOpt_RateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var startTimes []time.Time
+	taskFunc := func(ctx context.Context, v int) error {
+		mu.Lock()
+		startTimes = append(startTimes, time.Now())
+		mu.Unlock()
+		return nil
+	}
+
+	start := time.Now()
+	errMap := ExecTaskFuncOpt(context.Background(), taskFunc, []int{1, 2, 3, 4, 5, 6},
+		ExecTasksMaxConcurrency(3), ExecTasksRateLimit(NewRateLimiter(50, 2)))
+	assert.Equal(t, 0, len(errMap))
+	assert.Equal(t, 6, len(startTimes))
+	// 2 tasks start immediately, the other 4 tasks need to wait 20ms each
+	assert.True(t, time.Since(start) >= 75*time.Millisecond)
+}
+
+// nolint
+func Test_ExecTasksOpt_TimeoutAndRetry(t *testing.T) {
+	errTest := errors.New("test error")
+	errFatal := errors.New("fatal error")
+
+	t.Run("per task timeout", func(t *testing.T) {
+		taskFunc := func(ctx context.Context, v int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(v) * time.Millisecond):
+				return nil
+			}
+		}
+		errMap := ExecTaskFuncOpt(context.Background(), taskFunc, []int{1, 500, 2},
+			ExecTasksStopOnError(false), ExecTasksTimeout(50*time.Millisecond))
+		assert.Equal(t, 1, len(errMap))
+		assert.ErrorIs(t, errMap[1], context.DeadlineExceeded)
+	})
+
+	t.Run("retry failed tasks", func(t *testing.T) {
+		var mu sync.Mutex
+		attempts := map[int]int{}
+		taskFunc := func(ctx context.Context, v int) (int, error) {
+			mu.Lock()
+			attempts[v]++
+			n := attempts[v]
+			mu.Unlock()
+			if v == 3 {
+				return 0, errFatal
+			}
+			if n < v {
+				return 0, errTest
+			}
+			return v * 10, nil
+		}
+		results, errMap := ExecTaskFuncResultsOpt(context.Background(), taskFunc, []int{1, 2, 3, 4},
+			ExecTasksStopOnError(false),
+			ExecTasksRetry(5, time.Millisecond, ExecRetryIfErrorIs(errTest)))
+		assert.Equal(t, 1, len(errMap))
+		assert.ErrorIs(t, errMap[2], errFatal)
+		assert.Equal(t, []int{10, 20, 0, 40}, results)
+		assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1, 4: 4}, attempts)
+	})
+
+	t.Run("retry timed out attempts", func(t *testing.T) {
+		var count atomic.Int32
+		task := func(ctx context.Context) error {
+			if count.Add(1) < 3 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		}
+		errMap := ExecTasksOpt(context.Background(), []func(ctx context.Context) error{task},
+			ExecTasksTimeout(10*time.Millisecond), ExecTasksRetry(3, time.Millisecond))
+		assert.Equal(t, 0, len(errMap))
+		assert.Equal(t, int32(3), count.Load())
+
+		// The task timeout is retried even when only specific errors are retryable
+		count.Store(0)
+		errMap = ExecTasksOpt(context.Background(), []func(ctx context.Context) error{task},
+			ExecTasksTimeout(10*time.Millisecond), ExecTasksRetry(3, time.Millisecond, ExecRetryIfErrorIs(errTest)))
+		assert.Equal(t, 0, len(errMap))
+		assert.Equal(t, int32(3), count.Load())
+	})
+
+	t.Run("retry with attempt timeout", func(t *testing.T) {
+		var count atomic.Int32
+		task := func(ctx context.Context) error {
+			if count.Add(1) < 3 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		}
+		errMap := ExecTasksOpt(context.Background(), []func(ctx context.Context) error{task},
+			ExecTasksRetry(3, time.Millisecond, ExecRetryAttemptTimeout(10*time.Millisecond)))
+		assert.Equal(t, 0, len(errMap))
+		assert.Equal(t, int32(3), count.Load())
+
+		// The attempt timeout takes precedence over the task timeout
+		count.Store(0)
+		start := time.Now()
+		errMap = ExecTasksOpt(context.Background(), []func(ctx context.Context) error{task},
+			ExecTasksTimeout(time.Second),
+			ExecTasksRetry(3, time.Millisecond, ExecRetryAttemptTimeout(10*time.Millisecond)))
+		assert.Equal(t, 0, len(errMap))
+		assert.Equal(t, int32(3), count.Load())
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+// nolint
+func Test_ExecTasksOpt_Hooks(t *testing.T) {
+	errTest := errors.New("test error")
+	taskFunc := func(ctx context.Context, v int) error {
+		time.Sleep(time.Duration(v) * time.Millisecond)
+		if v == 30 {
+			return errTest
+		}
+		return nil
+	}
+
+	var startedCount atomic.Int32
+	var finished []int
+	var progress []int
+	var summary *ExecTasksSummary
+	errMap := ExecTaskFuncOpt(context.Background(), taskFunc, []int{10, 20, 30, 5},
+		ExecTasksStopOnError(false),
+		ExecTasksMaxConcurrency(2),
+		ExecTasksOnStart(func(index int) {
+			startedCount.Add(1)
+		}),
+		ExecTasksOnFinish(func(index int, duration time.Duration, err error) {
+			finished = append(finished, index)
+			assert.True(t, duration > 0)
+			if index == 2 {
+				assert.ErrorIs(t, err, errTest)
+			}
+		}),
+		ExecTasksOnProgress(func(done, total int) {
+			assert.Equal(t, 4, total)
+			progress = append(progress, done)
+		}),
+		ExecTasksOnComplete(func(s *ExecTasksSummary) {
+			summary = s
+		}),
+	)
+	assert.Equal(t, 1, len(errMap))
+	assert.Equal(t, int32(4), startedCount.Load())
+	assert.True(t, ContentEqual([]int{0, 1, 2, 3}, finished))
+	assert.Equal(t, []int{1, 2, 3, 4}, progress)
+
+	assert.NotNil(t, summary)
+	assert.Equal(t, 4, summary.TaskCount)
+	assert.Equal(t, 4, summary.StartedCount)
+	assert.Equal(t, 4, summary.FinishedCount)
+	assert.Equal(t, 1, summary.FailedCount)
+	assert.True(t, summary.MinTaskDuration >= 5*time.Millisecond)
+	assert.True(t, summary.MaxTaskDuration >= 30*time.Millisecond)
+	assert.True(t, summary.TaskDurations[2] >= 30*time.Millisecond)
+	assert.True(t, summary.AvgTaskDuration() >= 16*time.Millisecond)
+	assert.True(t, summary.Duration >= summary.MaxTaskDuration)
+}
+
+func Test_ExecTasksOpt_HooksTiming(t *testing.T) {
+	taskFunc := func(ctx context.Context, v int) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+
+	// Hooks are called as soon as the tasks finish, not after all the tasks are launched
+	start := time.Now()
+	var firstProgress time.Duration
+	var progress []int
+	errMap := ExecTaskFuncOpt(context.Background(), taskFunc, make([]int, 10),
+		ExecTasksMaxConcurrency(1),
+		ExecTasksOnProgress(func(done, total int) {
+			if done == 1 {
+				firstProgress = time.Since(start)
+			}
+			progress = append(progress, done)
+		}))
+	assert.Equal(t, 0, len(errMap))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, progress)
+	assert.True(t, firstProgress < 100*time.Millisecond)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+}
+
+// nolint
+func Test_ExecTasksOpt_Weight(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int64
+	weights := []int64{4, 1, 1, 2, 2, 10, 0}
+	taskFunc := func(ctx context.Context, i int) error {
+		mu.Lock()
+		running += Clamp(weights[i], 1, 4)
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running -= Clamp(weights[i], 1, 4)
+		mu.Unlock()
+		return nil
+	}
+
+	errMap := ExecTaskFuncOpt(context.Background(), taskFunc, []int{0, 1, 2, 3, 4, 5, 6},
+		ExecTasksMaxConcurrency(4),
+		ExecTasksWeight(func(index int) int64 { return weights[index] }))
+	assert.Equal(t, 0, len(errMap))
+	assert.Equal(t, int64(4), maxRunning)
+}
+
+func Test_ExecTasksOpt_Key(t *testing.T) {
+	var mu sync.Mutex
+	runningByKey := map[string]int{}
+	running, maxRunning := 0, 0
+	keys := []string{"a", "b", "a", "c", "a", "b"}
+	taskFunc := func(ctx context.Context, i int) error {
+		mu.Lock()
+		runningByKey[keys[i]]++
+		assert.Equal(t, 1, runningByKey[keys[i]])
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		runningByKey[keys[i]]--
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	errMap := ExecTaskFuncOpt(context.Background(), taskFunc, []int{0, 1, 2, 3, 4, 5},
+		ExecTasksKey(func(index int) string { return keys[index] }))
+	assert.Equal(t, 0, len(errMap))
+	assert.Equal(t, 3, maxRunning)
+}
@@ -0,0 +1,418 @@
+package gofn
+
+import (
+	"sync"
+	"time"
+)
+
+type DebounceConfig struct {
+	leading  bool
+	trailing bool
+	maxWait  time.Duration
+}
+
+type DebounceOption func(*DebounceConfig)
+
+// DebounceLeading sets whether to invoke the function on the leading edge of a burst (default is false)
+func DebounceLeading(leading bool) DebounceOption {
+	return func(config *DebounceConfig) {
+		config.leading = leading
+	}
+}
+
+// DebounceTrailing sets whether to invoke the function on the trailing edge of a burst (default is true)
+func DebounceTrailing(trailing bool) DebounceOption {
+	return func(config *DebounceConfig) {
+		config.trailing = trailing
+	}
+}
+
+// DebounceMaxWait sets the maximum time a pending invocation can be delayed during a continuous burst
+func DebounceMaxWait(maxWait time.Duration) DebounceOption {
+	return func(config *DebounceConfig) {
+		config.maxWait = maxWait
+	}
+}
+
+// DebouncerArg delays invoking a function until `wait` has elapsed since the last call.
+// All methods are safe for concurrent use.
+type DebouncerArg[T any] struct {
+	cfg      *DebounceConfig
+	wait     time.Duration
+	fn       func([]T)
+	keepAll  bool
+	mu       sync.Mutex
+	args     []T
+	active   bool
+	timer    *time.Timer
+	timerSeq uint64
+	maxTimer *time.Timer
+	maxSeq   uint64
+}
+
+func newDebouncer[T any](fn func([]T), keepAll bool, wait time.Duration, options []DebounceOption) *DebouncerArg[T] {
+	cfg := &DebounceConfig{
+		trailing: true,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	return &DebouncerArg[T]{cfg: cfg, wait: wait, fn: fn, keepAll: keepAll}
+}
+
+// DebounceLast creates a debouncer which passes the argument of the last call to the function
+func DebounceLast[T any](fn func(T), wait time.Duration, options ...DebounceOption) *DebouncerArg[T] {
+	return newDebouncer(func(args []T) {
+		fn(args[len(args)-1])
+	}, false, wait, options)
+}
+
+// DebounceAll creates a debouncer which passes the arguments of all the calls since the last
+// invocation to the function
+func DebounceAll[T any](fn func([]T), wait time.Duration, options ...DebounceOption) *DebouncerArg[T] {
+	return newDebouncer(fn, true, wait, options)
+}
+
+// Call schedules an invocation of the function with the argument
+func (d *DebouncerArg[T]) Call(arg T) {
+	d.mu.Lock()
+	d.args = collectCallArgs(d.args, arg, d.keepAll)
+	var args []T
+	if !d.active {
+		// A new burst starts
+		d.active = true
+		if d.cfg.leading {
+			args = d.takeArgs()
+		}
+	}
+	if d.cfg.maxWait > 0 && d.maxTimer == nil {
+		d.maxSeq++
+		seq := d.maxSeq
+		d.maxTimer = time.AfterFunc(d.cfg.maxWait, func() { d.onMaxWait(seq) })
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timerSeq++
+	seq := d.timerSeq
+	d.timer = time.AfterFunc(d.wait, func() { d.onWait(seq) })
+	d.mu.Unlock()
+
+	if len(args) > 0 {
+		d.fn(args)
+	}
+}
+
+func (d *DebouncerArg[T]) onWait(seq uint64) {
+	d.mu.Lock()
+	if seq != d.timerSeq {
+		d.mu.Unlock()
+		return
+	}
+	var args []T
+	if d.cfg.trailing {
+		args = d.takeArgs()
+	}
+	d.reset()
+	d.mu.Unlock()
+
+	if len(args) > 0 {
+		d.fn(args)
+	}
+}
+
+func (d *DebouncerArg[T]) onMaxWait(seq uint64) {
+	d.mu.Lock()
+	if seq != d.maxSeq {
+		d.mu.Unlock()
+		return
+	}
+	d.maxTimer = nil
+	var args []T
+	if d.cfg.trailing {
+		args = d.takeArgs()
+	}
+	d.mu.Unlock()
+
+	if len(args) > 0 {
+		d.fn(args)
+	}
+}
+
+// takeArgs takes the pending arguments, must be called with the lock held
+func (d *DebouncerArg[T]) takeArgs() []T {
+	args := d.args
+	d.args = nil
+	return args
+}
+
+// reset ends the current burst, must be called with the lock held
+func (d *DebouncerArg[T]) reset() {
+	d.active = false
+	d.args = nil
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.maxTimer != nil {
+		d.maxTimer.Stop()
+		d.maxTimer = nil
+	}
+	d.timerSeq++
+	d.maxSeq++
+}
+
+// Flush invokes the pending call immediately if there is one
+func (d *DebouncerArg[T]) Flush() {
+	d.mu.Lock()
+	args := d.takeArgs()
+	d.reset()
+	d.mu.Unlock()
+
+	if len(args) > 0 {
+		d.fn(args)
+	}
+}
+
+// Cancel cancels the pending call
+func (d *DebouncerArg[T]) Cancel() {
+	d.mu.Lock()
+	d.reset()
+	d.mu.Unlock()
+}
+
+// Pending returns true if there is a pending call
+func (d *DebouncerArg[T]) Pending() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.args) > 0
+}
+
+// Debouncer is the non-generic form of DebouncerArg
+type Debouncer struct {
+	d *DebouncerArg[struct{}]
+}
+
+// Debounce creates a debouncer which delays invoking the function until `wait` has elapsed
+// since the last call
+func Debounce(fn func(), wait time.Duration, options ...DebounceOption) *Debouncer {
+	return &Debouncer{d: newDebouncer(func([]struct{}) { fn() }, false, wait, options)}
+}
+
+// Call schedules an invocation of the function
+func (d *Debouncer) Call() {
+	d.d.Call(struct{}{})
+}
+
+// Flush invokes the pending call immediately if there is one
+func (d *Debouncer) Flush() {
+	d.d.Flush()
+}
+
+// Cancel cancels the pending call
+func (d *Debouncer) Cancel() {
+	d.d.Cancel()
+}
+
+// Pending returns true if there is a pending call
+func (d *Debouncer) Pending() bool {
+	return d.d.Pending()
+}
+
+type ThrottleConfig struct {
+	leading  bool
+	trailing bool
+}
+
+type ThrottleOption func(*ThrottleConfig)
+
+// ThrottleLeading sets whether to invoke the function immediately on the first call of an interval (default is true)
+func ThrottleLeading(leading bool) ThrottleOption {
+	return func(config *ThrottleConfig) {
+		config.leading = leading
+	}
+}
+
+// ThrottleTrailing sets whether to invoke the function at the end of an interval if there are
+// calls during the interval (default is true)
+func ThrottleTrailing(trailing bool) ThrottleOption {
+	return func(config *ThrottleConfig) {
+		config.trailing = trailing
+	}
+}
+
+// ThrottlerArg invokes a function at most once every `interval`.
+// All methods are safe for concurrent use.
+type ThrottlerArg[T any] struct {
+	cfg        *ThrottleConfig
+	interval   time.Duration
+	fn         func([]T)
+	keepAll    bool
+	mu         sync.Mutex
+	args       []T
+	lastInvoke time.Time
+	timer      *time.Timer
+	timerSeq   uint64
+}
+
+func newThrottler[T any](
+	fn func([]T),
+	keepAll bool,
+	interval time.Duration,
+	options []ThrottleOption,
+) *ThrottlerArg[T] {
+	cfg := &ThrottleConfig{
+		leading:  true,
+		trailing: true,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	return &ThrottlerArg[T]{cfg: cfg, interval: interval, fn: fn, keepAll: keepAll}
+}
+
+// ThrottleLast creates a throttler which passes the argument of the last call to the function
+func ThrottleLast[T any](fn func(T), interval time.Duration, options ...ThrottleOption) *ThrottlerArg[T] {
+	return newThrottler(func(args []T) {
+		fn(args[len(args)-1])
+	}, false, interval, options)
+}
+
+// ThrottleAll creates a throttler which passes the arguments of all the calls since the last
+// invocation to the function
+func ThrottleAll[T any](fn func([]T), interval time.Duration, options ...ThrottleOption) *ThrottlerArg[T] {
+	return newThrottler(fn, true, interval, options)
+}
+
+// Call invokes the function or schedules an invocation depending on the time of the last invocation
+func (t *ThrottlerArg[T]) Call(arg T) {
+	t.mu.Lock()
+	t.args = collectCallArgs(t.args, arg, t.keepAll)
+	var args []T
+	if t.timer == nil {
+		now := time.Now()
+		delay := t.interval
+		if t.cfg.leading {
+			delay = t.lastInvoke.Add(t.interval).Sub(now)
+		}
+		switch {
+		case delay <= 0:
+			args = t.takeArgs()
+			t.lastInvoke = now
+		case t.cfg.trailing:
+			t.timerSeq++
+			seq := t.timerSeq
+			t.timer = time.AfterFunc(delay, func() { t.onTimer(seq) })
+		default:
+			t.args = nil
+		}
+	}
+	t.mu.Unlock()
+
+	if len(args) > 0 {
+		t.fn(args)
+	}
+}
+
+func (t *ThrottlerArg[T]) onTimer(seq uint64) {
+	t.mu.Lock()
+	if seq != t.timerSeq {
+		t.mu.Unlock()
+		return
+	}
+	t.timer = nil
+	args := t.takeArgs()
+	if len(args) > 0 {
+		t.lastInvoke = time.Now()
+	}
+	t.mu.Unlock()
+
+	if len(args) > 0 {
+		t.fn(args)
+	}
+}
+
+// takeArgs takes the pending arguments, must be called with the lock held
+func (t *ThrottlerArg[T]) takeArgs() []T {
+	args := t.args
+	t.args = nil
+	return args
+}
+
+// stopTimer stops the scheduled invocation, must be called with the lock held
+func (t *ThrottlerArg[T]) stopTimer() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.timerSeq++
+}
+
+// Flush invokes the pending call immediately if there is one
+func (t *ThrottlerArg[T]) Flush() {
+	t.mu.Lock()
+	t.stopTimer()
+	args := t.takeArgs()
+	if len(args) > 0 {
+		t.lastInvoke = time.Now()
+	}
+	t.mu.Unlock()
+
+	if len(args) > 0 {
+		t.fn(args)
+	}
+}
+
+// Cancel cancels the pending call
+func (t *ThrottlerArg[T]) Cancel() {
+	t.mu.Lock()
+	t.stopTimer()
+	t.args = nil
+	t.mu.Unlock()
+}
+
+// Pending returns true if there is a pending call
+func (t *ThrottlerArg[T]) Pending() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.args) > 0
+}
+
+// Throttler is the non-generic form of ThrottlerArg
+type Throttler struct {
+	t *ThrottlerArg[struct{}]
+}
+
+// Throttle creates a throttler which invokes the function at most once every `interval`
+func Throttle(fn func(), interval time.Duration, options ...ThrottleOption) *Throttler {
+	return &Throttler{t: newThrottler(func([]struct{}) { fn() }, false, interval, options)}
+}
+
+// Call invokes the function or schedules an invocation depending on the time of the last invocation
+func (t *Throttler) Call() {
+	t.t.Call(struct{}{})
+}
+
+// Flush invokes the pending call immediately if there is one
+func (t *Throttler) Flush() {
+	t.t.Flush()
+}
+
+// Cancel cancels the pending call
+func (t *Throttler) Cancel() {
+	t.t.Cancel()
+}
+
+// Pending returns true if there is a pending call
+func (t *Throttler) Pending() bool {
+	return t.t.Pending()
+}
+
+// collectCallArgs adds the argument of a call to the pending ones
+func collectCallArgs[T any](args []T, arg T, keepAll bool) []T {
+	if keepAll || len(args) == 0 {
+		return append(args, arg)
+	}
+	args[0] = arg
+	return args
+}
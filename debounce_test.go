@@ -0,0 +1,180 @@
+package gofn
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_Debounce(t *testing.T) {
+	t.Run("trailing", func(t *testing.T) {
+		var count atomic.Int32
+		d := Debounce(func() { count.Add(1) }, 30*time.Millisecond)
+		for i := 0; i < 5; i++ {
+			d.Call()
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.Equal(t, int32(0), count.Load())
+		assert.True(t, d.Pending())
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(1), count.Load())
+		assert.False(t, d.Pending())
+	})
+
+	t.Run("leading", func(t *testing.T) {
+		var count atomic.Int32
+		d := Debounce(func() { count.Add(1) }, 30*time.Millisecond,
+			DebounceLeading(true), DebounceTrailing(false))
+		for i := 0; i < 5; i++ {
+			d.Call()
+		}
+		assert.Equal(t, int32(1), count.Load())
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(1), count.Load())
+
+		// A new burst
+		d.Call()
+		assert.Equal(t, int32(2), count.Load())
+	})
+
+	t.Run("leading and trailing", func(t *testing.T) {
+		var count atomic.Int32
+		d := Debounce(func() { count.Add(1) }, 30*time.Millisecond, DebounceLeading(true))
+		d.Call()
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(1), count.Load()) // single call invokes once only
+
+		d.Call()
+		d.Call()
+		assert.Equal(t, int32(2), count.Load())
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(3), count.Load())
+	})
+
+	t.Run("max wait", func(t *testing.T) {
+		var count atomic.Int32
+		d := Debounce(func() { count.Add(1) }, 30*time.Millisecond, DebounceMaxWait(50*time.Millisecond))
+		for i := 0; i < 10; i++ {
+			d.Call()
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.True(t, count.Load() >= 1)
+		d.Cancel()
+	})
+
+	t.Run("flush and cancel", func(t *testing.T) {
+		var count atomic.Int32
+		d := Debounce(func() { count.Add(1) }, 20*time.Millisecond)
+		d.Call()
+		d.Flush()
+		assert.Equal(t, int32(1), count.Load())
+		d.Flush() // nothing pending
+		assert.Equal(t, int32(1), count.Load())
+
+		d.Call()
+		d.Cancel()
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, int32(1), count.Load())
+	})
+
+	t.Run("last and all arguments", func(t *testing.T) {
+		var mu sync.Mutex
+		var last int
+		var all []int
+		d1 := DebounceLast(func(v int) {
+			mu.Lock()
+			last = v
+			mu.Unlock()
+		}, 20*time.Millisecond)
+		d2 := DebounceAll(func(v []int) {
+			mu.Lock()
+			all = v
+			mu.Unlock()
+		}, 20*time.Millisecond)
+		for i := 1; i <= 5; i++ {
+			d1.Call(i)
+			d2.Call(i)
+		}
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		assert.Equal(t, 5, last)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, all)
+		mu.Unlock()
+	})
+}
+
+// nolint
+func Test_Throttle(t *testing.T) {
+	t.Run("leading and trailing", func(t *testing.T) {
+		var count atomic.Int32
+		th := Throttle(func() { count.Add(1) }, 30*time.Millisecond)
+		th.Call()
+		assert.Equal(t, int32(1), count.Load())
+		th.Call()
+		th.Call()
+		assert.Equal(t, int32(1), count.Load())
+		assert.True(t, th.Pending())
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(2), count.Load())
+	})
+
+	t.Run("rate is limited", func(t *testing.T) {
+		var count atomic.Int32
+		th := Throttle(func() { count.Add(1) }, 20*time.Millisecond)
+		start := time.Now()
+		for time.Since(start) < 100*time.Millisecond {
+			th.Call()
+			time.Sleep(time.Millisecond)
+		}
+		th.Cancel()
+		assert.True(t, count.Load() >= 3 && count.Load() <= 7)
+	})
+
+	t.Run("no trailing", func(t *testing.T) {
+		var count atomic.Int32
+		th := Throttle(func() { count.Add(1) }, 30*time.Millisecond, ThrottleTrailing(false))
+		th.Call()
+		th.Call()
+		assert.False(t, th.Pending())
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), count.Load())
+		th.Call()
+		assert.Equal(t, int32(2), count.Load())
+	})
+
+	t.Run("no leading", func(t *testing.T) {
+		var mu sync.Mutex
+		var all [][]int
+		th := ThrottleAll(func(v []int) {
+			mu.Lock()
+			all = append(all, v)
+			mu.Unlock()
+		}, 30*time.Millisecond, ThrottleLeading(false))
+		th.Call(1)
+		th.Call(2)
+		mu.Lock()
+		assert.Equal(t, 0, len(all))
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		th.Call(3)
+		th.Flush()
+		mu.Lock()
+		assert.Equal(t, [][]int{{1, 2}, {3}}, all)
+		mu.Unlock()
+	})
+
+	t.Run("last argument", func(t *testing.T) {
+		var last atomic.Int32
+		th := ThrottleLast(func(v int32) { last.Store(v) }, 20*time.Millisecond)
+		th.Call(1)
+		th.Call(2)
+		th.Call(3)
+		assert.Equal(t, int32(1), last.Load())
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, int32(3), last.Load())
+	})
+}
@@ -3,6 +3,8 @@ package gofn
 import (
 	"errors"
 	"fmt"
+	"runtime/debug"
+	"time"
 )
 
 var (
@@ -10,6 +12,17 @@ var (
 	ErrIndexOutOfRange = errors.New("index out of range")
 	ErrOverflow        = errors.New("overflow")
 	ErrPanic           = errors.New("panic occurred")
+	ErrPoolClosed      = errors.New("pool is closed")
+	ErrBatcherClosed   = errors.New("batcher is closed")
+	ErrCircuitOpen     = errors.New("circuit breaker is open")
+
+	ErrTaskGraphDuplicate  = errors.New("duplicate task")
+	ErrTaskGraphUnknownDep = errors.New("unknown task dependency")
+	ErrTaskGraphCycle      = errors.New("task dependency cycle")
+	ErrTaskSkipped         = errors.New("task skipped")
+
+	ErrSupervisorStopped   = errors.New("supervisor is stopped")
+	ErrSupervisorDuplicate = errors.New("duplicate worker")
 )
 
 // ErrWrap wraps an error with a message placed in the right
@@ -50,3 +63,67 @@ func ErrUnwrapToRoot(err error) error {
 		rootErr = e
 	}
 }
+
+// PanicError represents a panic recovered by the library functions.
+// The error matches ErrPanic with errors.Is(). When the panic value is an error,
+// it also matches that error.
+type PanicError struct {
+	// Value the value passed to panic()
+	Value any
+	// Stack the stack trace of the goroutine captured at recovery
+	Stack []byte
+	// TaskIndex index of the task which panicked, -1 when not applicable
+	TaskIndex int
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPanic, e.Value)
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+	return []error{ErrPanic}
+}
+
+// newPanicError creates a PanicError from a recovered value, must be called in the deferred
+// function which recovers the panic to capture the right stack trace
+func newPanicError(value any, taskIndex int) *PanicError {
+	return &PanicError{
+		Value:     value,
+		Stack:     debug.Stack(),
+		TaskIndex: taskIndex,
+	}
+}
+
+// RetryAfterError is implemented by errors telling how long to wait before retrying,
+// such as an error of an HTTP 429 response having the Retry-After header.
+// The retry functions use this delay when the error chain contains such an error.
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// ErrWithRetryAfter wraps an error with a hint of the delay before retrying.
+// The returned error matches the wrapped one with errors.Is().
+func ErrWithRetryAfter(err error, retryAfter time.Duration) error {
+	return &retryAfterError{err: err, retryAfter: retryAfter}
+}
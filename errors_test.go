@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +47,42 @@ func Test_ErrUnwrapToRoot(t *testing.T) {
 	assert.Equal(t, e1, ErrUnwrapToRoot(e2))
 	assert.Equal(t, e1, ErrUnwrapToRoot(e3))
 }
+
+func Test_PanicError(t *testing.T) {
+	e := errors.New("err")
+
+	var panicErr error
+	func() {
+		defer func() {
+			panicErr = newPanicError(recover(), 2)
+		}()
+		panic(e)
+	}()
+	assert.ErrorIs(t, panicErr, ErrPanic)
+	assert.ErrorIs(t, panicErr, e)
+	assert.Equal(t, "panic occurred: err", panicErr.Error())
+
+	var pe *PanicError
+	assert.True(t, errors.As(panicErr, &pe))
+	assert.Equal(t, e, pe.Value)
+	assert.Equal(t, 2, pe.TaskIndex)
+	assert.Contains(t, string(pe.Stack), "Test_PanicError")
+
+	// Non-error panic value
+	panicErr = newPanicError("oops", -1)
+	assert.ErrorIs(t, panicErr, ErrPanic)
+	assert.Equal(t, "panic occurred: oops", panicErr.Error())
+	assert.Equal(t, []error{ErrPanic}, ErrUnwrap(panicErr))
+}
+
+func Test_ErrWithRetryAfter(t *testing.T) {
+	e := errors.New("too many requests")
+	err := ErrWrapL("call service", ErrWithRetryAfter(e, time.Second))
+	assert.ErrorIs(t, err, e)
+	assert.Equal(t, "call service: too many requests", err.Error())
+
+	var retryAfterErr RetryAfterError
+	assert.True(t, errors.As(err, &retryAfterErr))
+	assert.Equal(t, time.Second, retryAfterErr.RetryAfter())
+	assert.False(t, errors.As(e, &retryAfterErr))
+}
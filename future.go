@@ -0,0 +1,166 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// Future represents the result of an asynchronous computation
+type Future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Go executes the function in a new goroutine and returns a future of its result.
+// A panic in the function is returned as ErrPanic.
+func Go[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				f.err = newPanicError(r, -1)
+			}
+			close(f.done)
+		}()
+		f.val, f.err = fn(ctx)
+	}()
+	return f
+}
+
+// Done returns a channel which is closed when the computation completes
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for the computation to complete and returns its result.
+// Returns the context error if the context is done first, the computation is not affected.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zeroT T
+		return zeroT, ctx.Err() // nolint: wrapcheck
+	}
+}
+
+// FutureThen chains a computation which is executed with the result of the future when it succeeds.
+// If the future fails, the returned future fails with the same error.
+func FutureThen[T any, U any](
+	ctx context.Context,
+	f *Future[T],
+	fn func(ctx context.Context, v T) (U, error),
+) *Future[U] {
+	return Go(ctx, func(ctx context.Context) (U, error) {
+		v, err := f.Await(ctx)
+		if err != nil {
+			var zeroU U
+			return zeroU, err
+		}
+		return fn(ctx, v)
+	})
+}
+
+// FutureMap transforms the result of the future when it succeeds
+func FutureMap[T any, U any](ctx context.Context, f *Future[T], mapFunc func(v T) U) *Future[U] {
+	return FutureThen(ctx, f, func(_ context.Context, v T) (U, error) {
+		return mapFunc(v), nil
+	})
+}
+
+// awaitNext waits for one of the futures to complete, returns its index
+func awaitNext[T any](ctx context.Context, futures []*Future[T]) (int, error) {
+	cases := make([]reflect.SelectCase, 0, len(futures)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	for _, f := range futures {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(f.done)})
+	}
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == 0 {
+		return -1, ctx.Err() // nolint: wrapcheck
+	}
+	return chosen - 1, nil
+}
+
+// AwaitAll waits for all the futures to complete and returns their results in order.
+// Returns on the first failure without waiting for the others.
+func AwaitAll[T any](ctx context.Context, futures ...*Future[T]) ([]T, error) {
+	results := make([]T, len(futures))
+	pending := make([]*Future[T], len(futures))
+	indexes := make([]int, len(futures))
+	copy(pending, futures)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	for len(pending) > 0 {
+		i, err := awaitNext(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+		f := pending[i]
+		if f.err != nil {
+			return nil, f.err
+		}
+		results[indexes[i]] = f.val
+		RemoveAt(&pending, i)
+		RemoveAt(&indexes, i)
+	}
+	return results, nil
+}
+
+// AwaitAny waits for the first future to complete and returns its index and result
+func AwaitAny[T any](ctx context.Context, futures ...*Future[T]) (int, T, error) {
+	var zeroT T
+	if len(futures) == 0 {
+		return -1, zeroT, ErrEmpty
+	}
+	i, err := awaitNext(ctx, futures)
+	if err != nil {
+		return -1, zeroT, err
+	}
+	return i, futures[i].val, futures[i].err
+}
+
+// AwaitFirstSuccess waits for the first future to succeed and returns its index and result.
+// If all the futures fail, returns all the errors joined.
+func AwaitFirstSuccess[T any](ctx context.Context, futures ...*Future[T]) (int, T, error) {
+	var zeroT T
+	if len(futures) == 0 {
+		return -1, zeroT, ErrEmpty
+	}
+	pending := make([]*Future[T], len(futures))
+	indexes := make([]int, len(futures))
+	copy(pending, futures)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	errs := make([]error, len(futures))
+	for len(pending) > 0 {
+		i, err := awaitNext(ctx, pending)
+		if err != nil {
+			return -1, zeroT, err
+		}
+		f := pending[i]
+		if f.err == nil {
+			return indexes[i], f.val, nil
+		}
+		errs[indexes[i]] = f.err
+		RemoveAt(&pending, i)
+		RemoveAt(&indexes, i)
+	}
+	return -1, zeroT, errors.Join(errs...)
+}
+
+// ExecTasksAsFutures starts the tasks concurrently and returns their futures in the same order
+func ExecTasksAsFutures(ctx context.Context, tasks ...func(ctx context.Context) error) []*Future[struct{}] {
+	futures := make([]*Future[struct{}], len(tasks))
+	for i := range tasks {
+		task := tasks[i]
+		futures[i] = Go(ctx, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, task(ctx)
+		})
+	}
+	return futures
+}
@@ -0,0 +1,159 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFuture[T any](v T, err error, delay time.Duration) *Future[T] {
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		time.Sleep(delay)
+		return v, err
+	})
+}
+
+// nolint
+func Test_Future(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("await", func(t *testing.T) {
+		f := newTestFuture(123, nil, 10*time.Millisecond)
+		v, err := f.Await(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 123, v)
+		<-f.Done()
+
+		f = newTestFuture(0, errTest, 0)
+		_, err = f.Await(context.Background())
+		assert.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("await with context done", func(t *testing.T) {
+		f := newTestFuture(123, nil, 50*time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := f.Await(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The computation is not affected
+		v, err := f.Await(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 123, v)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		f := Go(context.Background(), func(ctx context.Context) (int, error) {
+			panic(errTest)
+		})
+		_, err := f.Await(context.Background())
+		assert.ErrorIs(t, err, ErrPanic)
+		assert.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("then and map", func(t *testing.T) {
+		ctx := context.Background()
+		f1 := newTestFuture(2, nil, 0)
+		f2 := FutureThen(ctx, f1, func(ctx context.Context, v int) (int, error) { return v * 10, nil })
+		f3 := FutureMap(ctx, f2, func(v int) string { return fmt.Sprintf("v=%d", v) })
+		v, err := f3.Await(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "v=20", v)
+
+		f4 := FutureMap(ctx, newTestFuture(0, errTest, 0), func(v int) int { return v })
+		_, err = f4.Await(ctx)
+		assert.ErrorIs(t, err, errTest)
+	})
+}
+
+// nolint
+func Test_AwaitAll(t *testing.T) {
+	errTest := errors.New("test error")
+	ctx := context.Background()
+
+	results, err := AwaitAll(ctx,
+		newTestFuture(1, nil, 20*time.Millisecond),
+		newTestFuture(2, nil, 0),
+		newTestFuture(3, nil, 10*time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, results)
+
+	results, err = AwaitAll[int](ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, results)
+
+	// Fail fast
+	start := time.Now()
+	_, err = AwaitAll(ctx,
+		newTestFuture(1, nil, time.Second),
+		newTestFuture(0, errTest, 10*time.Millisecond))
+	assert.ErrorIs(t, err, errTest)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = AwaitAll(ctxTimeout, newTestFuture(1, nil, time.Second))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// nolint
+func Test_AwaitAny(t *testing.T) {
+	errTest := errors.New("test error")
+	ctx := context.Background()
+
+	i, v, err := AwaitAny(ctx,
+		newTestFuture(1, nil, 50*time.Millisecond),
+		newTestFuture(2, errTest, 0))
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, 1, i)
+	assert.Equal(t, 2, v)
+
+	_, _, err = AwaitAny[int](ctx)
+	assert.ErrorIs(t, err, ErrEmpty)
+}
+
+// nolint
+func Test_AwaitFirstSuccess(t *testing.T) {
+	errTest1 := errors.New("test error 1")
+	errTest2 := errors.New("test error 2")
+	ctx := context.Background()
+
+	i, v, err := AwaitFirstSuccess(ctx,
+		newTestFuture(1, nil, 30*time.Millisecond),
+		newTestFuture(2, errTest1, 0),
+		newTestFuture(3, nil, 10*time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, 3, v)
+
+	i, _, err = AwaitFirstSuccess(ctx,
+		newTestFuture(1, errTest1, 10*time.Millisecond),
+		newTestFuture(2, errTest2, 0))
+	assert.Equal(t, -1, i)
+	assert.ErrorIs(t, err, errTest1)
+	assert.ErrorIs(t, err, errTest2)
+
+	_, _, err = AwaitFirstSuccess[int](ctx)
+	assert.ErrorIs(t, err, ErrEmpty)
+}
+
+// nolint
+func Test_ExecTasksAsFutures(t *testing.T) {
+	errTest := errors.New("test error")
+	futures := ExecTasksAsFutures(context.Background(),
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { return errTest },
+	)
+	assert.Equal(t, 2, len(futures))
+	_, err := futures[0].Await(context.Background())
+	assert.Nil(t, err)
+	_, err = futures[1].Await(context.Background())
+	assert.ErrorIs(t, err, errTest)
+
+	_, err = AwaitAll(context.Background(), futures...)
+	assert.ErrorIs(t, err, errTest)
+}
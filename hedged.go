@@ -0,0 +1,107 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ExecHedged executes the first function, then launches the next one every time no result arrives
+// within the delay or a launched function fails. Returns the result of the first success and cancels
+// the context passed to the others. If all the functions fail, returns all the errors joined.
+// A panic in a function is returned as ErrPanic.
+func ExecHedged[T any](
+	ctx context.Context,
+	delay time.Duration,
+	fns ...func(ctx context.Context) (T, error),
+) (T, error) {
+	return execHedged(ctx, delay, 1, fns)
+}
+
+// ExecFirstSuccess executes all the functions concurrently and returns the result of the first success,
+// the context passed to the others is canceled. If all the functions fail, returns all the errors joined.
+// A panic in a function is returned as ErrPanic.
+func ExecFirstSuccess[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) (T, error) {
+	return execHedged(ctx, 0, len(fns), fns)
+}
+
+// nolint: gocognit
+func execHedged[T any](
+	ctx context.Context,
+	delay time.Duration,
+	initialCount int,
+	fns []func(ctx context.Context) (T, error),
+) (T, error) {
+	var zeroT T
+	if len(fns) == 0 {
+		return zeroT, ErrEmpty
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type hedgedResult struct {
+		index int
+		val   T
+		err   error
+	}
+	// Buffered, so the functions finishing after the return never block
+	resultChan := make(chan *hedgedResult, len(fns))
+	launched := 0
+	launchNext := func() {
+		i, fn := launched, fns[launched]
+		launched++
+		go func() {
+			res := &hedgedResult{index: i}
+			defer func() {
+				if r := recover(); r != nil {
+					res.err = newPanicError(r, i)
+				}
+				resultChan <- res
+			}()
+			res.val, res.err = fn(ctx)
+		}()
+	}
+	for launched < initialCount {
+		launchNext()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	resetTimer := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(delay)
+	}
+
+	errs := make([]error, len(fns))
+	failed := 0
+	for failed < len(fns) {
+		var timerChan <-chan time.Time
+		if launched < len(fns) {
+			timerChan = timer.C
+		}
+		select {
+		case res := <-resultChan:
+			if res.err == nil {
+				return res.val, nil
+			}
+			errs[res.index] = res.err
+			failed++
+			if launched < len(fns) {
+				launchNext()
+				resetTimer()
+			}
+		case <-timerChan:
+			launchNext()
+			timer.Reset(delay)
+		case <-ctx.Done():
+			return zeroT, ctx.Err() // nolint: wrapcheck
+		}
+	}
+	return zeroT, errors.Join(errs...)
+}
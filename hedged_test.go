@@ -0,0 +1,116 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_ExecHedged(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("first function succeeds in time", func(t *testing.T) {
+		var launched atomic.Int32
+		fn := func(ctx context.Context) (int, error) {
+			return int(launched.Add(1)), nil
+		}
+		v, err := ExecHedged(context.Background(), 50*time.Millisecond, fn, fn, fn)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, v)
+		time.Sleep(70 * time.Millisecond)
+		assert.Equal(t, int32(1), launched.Load())
+	})
+
+	t.Run("slow function is hedged", func(t *testing.T) {
+		var slowCanceled atomic.Bool
+		start := time.Now()
+		v, err := ExecHedged(context.Background(), 20*time.Millisecond,
+			func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				slowCanceled.Store(true)
+				return 0, ctx.Err()
+			},
+			func(ctx context.Context) (int, error) {
+				return 2, nil
+			},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, v)
+		assert.True(t, time.Since(start) >= 20*time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		assert.True(t, slowCanceled.Load())
+	})
+
+	t.Run("failure launches the next function immediately", func(t *testing.T) {
+		start := time.Now()
+		v, err := ExecHedged(context.Background(), time.Second,
+			func(ctx context.Context) (int, error) { return 0, errTest },
+			func(ctx context.Context) (int, error) { panic("boom") },
+			func(ctx context.Context) (int, error) { return 3, nil },
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, v)
+		assert.True(t, time.Since(start) < 500*time.Millisecond)
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		errTest2 := errors.New("test error 2")
+		_, err := ExecHedged(context.Background(), time.Millisecond,
+			func(ctx context.Context) (int, error) { return 0, errTest },
+			func(ctx context.Context) (int, error) { return 0, errTest2 },
+		)
+		assert.ErrorIs(t, err, errTest)
+		assert.ErrorIs(t, err, errTest2)
+
+		_, err = ExecHedged[int](context.Background(), time.Millisecond)
+		assert.ErrorIs(t, err, ErrEmpty)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := ExecHedged(ctx, time.Millisecond, func(ctx context.Context) (int, error) {
+			time.Sleep(100 * time.Millisecond)
+			return 1, nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// nolint
+func Test_ExecFirstSuccess(t *testing.T) {
+	errTest := errors.New("test error")
+
+	var started atomic.Int32
+	v, err := ExecFirstSuccess(context.Background(),
+		func(ctx context.Context) (int, error) {
+			started.Add(1)
+			return 0, errTest
+		},
+		func(ctx context.Context) (int, error) {
+			started.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return 2, nil
+		},
+		func(ctx context.Context) (int, error) {
+			started.Add(1)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, int32(3), started.Load())
+
+	_, err = ExecFirstSuccess(context.Background(),
+		func(ctx context.Context) (int, error) { return 0, errTest },
+		func(ctx context.Context) (int, error) { panic("boom") },
+	)
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorIs(t, err, ErrPanic)
+}
@@ -0,0 +1,118 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+)
+
+type keyedLimiterEntry struct {
+	sem  *Semaphore
+	refs int
+}
+
+// KeyedLimiter limits the number of concurrent holders per key. Keys having no holder and no waiter
+// are removed, so the memory doesn't grow with the number of keys ever used.
+type KeyedLimiter[K comparable] struct {
+	limit   int64
+	mu      sync.Mutex
+	entries map[K]*keyedLimiterEntry
+}
+
+// NewKeyedLimiter creates a keyed limiter allowing at most `limit` concurrent holders per key
+func NewKeyedLimiter[K comparable](limit int64) *KeyedLimiter[K] {
+	return &KeyedLimiter[K]{limit: limit}
+}
+
+// ref gets the entry of the key and registers a reference to it
+func (l *KeyedLimiter[K]) ref(key K) *keyedLimiterEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries == nil {
+		l.entries = map[K]*keyedLimiterEntry{}
+	}
+	entry := l.entries[key]
+	if entry == nil {
+		entry = &keyedLimiterEntry{sem: NewSemaphore(Max(l.limit, 1))}
+		l.entries[key] = entry
+	}
+	entry.refs++
+	return entry
+}
+
+// unref removes a reference to the entry of the key, the entry is removed when it's idle
+func (l *KeyedLimiter[K]) unref(key K) *keyedLimiterEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := l.entries[key]
+	if entry == nil {
+		panic("gofn: keyed limiter released more than held")
+	}
+	entry.refs--
+	if entry.refs == 0 {
+		delete(l.entries, key)
+	}
+	return entry
+}
+
+// Acquire acquires a slot of the key, blocking until a slot is available or the context is done
+func (l *KeyedLimiter[K]) Acquire(ctx context.Context, key K) error {
+	entry := l.ref(key)
+	if err := entry.sem.Acquire(ctx, 1); err != nil {
+		l.unref(key)
+		return err
+	}
+	return nil
+}
+
+// TryAcquire acquires a slot of the key without blocking, returns false if no slot is available
+func (l *KeyedLimiter[K]) TryAcquire(key K) bool {
+	entry := l.ref(key)
+	if !entry.sem.TryAcquire(1) {
+		l.unref(key)
+		return false
+	}
+	return true
+}
+
+// Release releases a slot of the key. Panics if the key is released more than acquired.
+func (l *KeyedLimiter[K]) Release(key K) {
+	l.unref(key).sem.Release(1)
+}
+
+// Len returns the number of keys which are held or waited for
+func (l *KeyedLimiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// KeyedMutex is a mutex per key: holders of the same key are serialized, holders of different keys
+// don't block each other. Idle keys are removed. The zero value is an unlocked mutex ready to use.
+type KeyedMutex[K comparable] struct {
+	limiter KeyedLimiter[K]
+}
+
+// Lock locks the key, blocking until the key is available
+func (m *KeyedMutex[K]) Lock(key K) {
+	_ = m.limiter.Acquire(context.Background(), key)
+}
+
+// LockCtx locks the key, blocking until the key is available or the context is done
+func (m *KeyedMutex[K]) LockCtx(ctx context.Context, key K) error {
+	return m.limiter.Acquire(ctx, key)
+}
+
+// TryLock tries to lock the key without blocking, returns false if the key is locked
+func (m *KeyedMutex[K]) TryLock(key K) bool {
+	return m.limiter.TryAcquire(key)
+}
+
+// Unlock unlocks the key. Panics if the key is not locked.
+func (m *KeyedMutex[K]) Unlock(key K) {
+	m.limiter.Release(key)
+}
+
+// Len returns the number of keys which are locked or waited for
+func (m *KeyedMutex[K]) Len() int {
+	return m.limiter.Len()
+}
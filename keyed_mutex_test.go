@@ -0,0 +1,87 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_KeyedMutex(t *testing.T) {
+	t.Run("serialize per key", func(t *testing.T) {
+		var m KeyedMutex[int]
+		var running [3]atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 30; i++ {
+			key := i % 3
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.Lock(key)
+				defer m.Unlock(key)
+				assert.Equal(t, int32(1), running[key].Add(1))
+				time.Sleep(time.Millisecond)
+				running[key].Add(-1)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("TryLock and LockCtx", func(t *testing.T) {
+		var m KeyedMutex[string]
+		assert.True(t, m.TryLock("a"))
+		assert.False(t, m.TryLock("a"))
+		assert.True(t, m.TryLock("b"))
+		assert.Equal(t, 2, m.Len())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, m.LockCtx(ctx, "a"), context.DeadlineExceeded)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			m.Unlock("a")
+		}()
+		assert.Nil(t, m.LockCtx(context.Background(), "a"))
+		m.Unlock("a")
+		m.Unlock("b")
+		assert.Equal(t, 0, m.Len())
+
+		assert.Panics(t, func() { m.Unlock("a") })
+	})
+}
+
+// nolint
+func Test_KeyedLimiter(t *testing.T) {
+	l := NewKeyedLimiter[string](2)
+	assert.True(t, l.TryAcquire("a"))
+	assert.True(t, l.TryAcquire("a"))
+	assert.False(t, l.TryAcquire("a"))
+	assert.True(t, l.TryAcquire("b"))
+	assert.Equal(t, 2, l.Len())
+
+	acquired := make(chan struct{})
+	go func() {
+		assert.Nil(t, l.Acquire(context.Background(), "a"))
+		close(acquired)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-acquired:
+		assert.Fail(t, "acquired while the key is full")
+	default:
+	}
+	l.Release("a")
+	<-acquired
+
+	l.Release("a")
+	l.Release("a")
+	l.Release("b")
+	assert.Equal(t, 0, l.Len())
+	assert.Panics(t, func() { l.Release("b") })
+}
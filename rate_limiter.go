@@ -0,0 +1,100 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter implements the token bucket algorithm. The bucket holds at most `burst` tokens
+// and is refilled at `rate` tokens per second. Every event consumes one token.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastTime time.Time
+}
+
+// NewRateLimiter creates a rate limiter which allows `rate` events per second with bursts of
+// at most `burst` events. The bucket is initially full. A non-positive rate means no limit.
+func NewRateLimiter(rate float64, burst uint) *RateLimiter {
+	if burst == 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastTime: time.Now(),
+	}
+}
+
+// refill adds the tokens generated since the last update, must be called with the lock held
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.lastTime)
+	l.lastTime = now
+	if elapsed <= 0 {
+		return
+	}
+	l.tokens += elapsed.Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// Allow reports whether an event may happen now. If so, a token is consumed.
+func (l *RateLimiter) Allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until an event is allowed to happen or the context is done.
+// Waiters reserve their tokens in the order of calling, so they are served in FIFO order.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err // nolint: wrapcheck
+	}
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.refill(time.Now())
+	l.tokens--
+	var waitDuration time.Duration
+	if l.tokens < 0 {
+		waitDuration = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if waitDuration <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(waitDuration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give back the reserved token
+		l.mu.Lock()
+		l.refill(time.Now())
+		l.tokens++
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.mu.Unlock()
+		return ctx.Err() // nolint: wrapcheck
+	}
+}
@@ -0,0 +1,51 @@
+package gofn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	t.Run("allow", func(t *testing.T) {
+		limiter := NewRateLimiter(10, 3)
+		assert.True(t, limiter.Allow())
+		assert.True(t, limiter.Allow())
+		assert.True(t, limiter.Allow())
+		assert.False(t, limiter.Allow())
+		time.Sleep(120 * time.Millisecond)
+		assert.True(t, limiter.Allow())
+		assert.False(t, limiter.Allow())
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		limiter := NewRateLimiter(0, 1)
+		for i := 0; i < 100; i++ {
+			assert.True(t, limiter.Allow())
+			assert.Nil(t, limiter.Wait(context.Background()))
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		limiter := NewRateLimiter(100, 1)
+		start := time.Now()
+		for i := 0; i < 6; i++ {
+			assert.Nil(t, limiter.Wait(context.Background()))
+		}
+		// 1st event uses the initial token, the others wait 10ms each
+		elapsed := time.Since(start)
+		assert.True(t, elapsed >= 45*time.Millisecond && elapsed < 200*time.Millisecond)
+	})
+
+	t.Run("wait with context done", func(t *testing.T) {
+		limiter := NewRateLimiter(1, 1)
+		assert.True(t, limiter.Allow())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+		assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,161 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Retrier executes functions with retry using the same settings as ExecRetry().
+// A retrier is built once and never modified, so it can be shared between goroutines.
+type Retrier struct {
+	cfg        ExecRetryConfig
+	maxRetries int
+}
+
+// NewRetrier creates a retrier. maxRetries is the maximum number of retries after the first attempt,
+// pass a negative value to retry until success.
+func NewRetrier(maxRetries int, delay time.Duration, options ...ExecRetryOption) *Retrier {
+	r := &Retrier{
+		cfg: ExecRetryConfig{
+			kind:  execRetryFixedDelay,
+			delay: delay,
+		},
+		maxRetries: maxRetries,
+	}
+	for _, option := range options {
+		option(&r.cfg)
+	}
+	return r
+}
+
+// RetryAttempt information of an attempt passed to the attempt-aware retry functions
+type RetryAttempt struct {
+	// Number number of the attempt starting from 1
+	Number int
+	// RemainingRetries number of retries left after this attempt, -1 means unlimited
+	RemainingRetries int
+}
+
+// IsLast returns true if this is the last attempt
+func (a RetryAttempt) IsLast() bool {
+	return a.RemainingRetries == 0
+}
+
+// Do executes the function until it succeeds or the retry budget is exhausted.
+// Returns the error of the last attempt.
+func (r *Retrier) Do(fn func() error) error {
+	return r.DoCtx(context.Background(), fn)
+}
+
+// DoCtx executes the function until it succeeds or the retry budget is exhausted.
+// Returns the context error if the context is done while waiting between attempts.
+func (r *Retrier) DoCtx(ctx context.Context, fn func() error) error {
+	_, err := retrierExec(ctx, r, false, func(context.Context, RetryAttempt) (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// DoAttempt executes the function with retry, the function receives the attempt information, so it can
+// change its behavior on later attempts, such as falling back to another endpoint.
+func (r *Retrier) DoAttempt(ctx context.Context, fn func(ctx context.Context, attempt RetryAttempt) error) error {
+	_, err := retrierExec(ctx, r, true, func(ctx context.Context, attempt RetryAttempt) (struct{}, error) {
+		return struct{}{}, fn(ctx, attempt)
+	})
+	return err
+}
+
+// RetrierDo executes the function returning a result using the retrier
+func RetrierDo[T any](r *Retrier, fn func() (T, error)) (T, error) {
+	return RetrierDoCtx(context.Background(), r, fn)
+}
+
+// RetrierDoCtx executes the function returning a result using the retrier.
+// Returns the context error if the context is done while waiting between attempts.
+func RetrierDoCtx[T any](ctx context.Context, r *Retrier, fn func() (T, error)) (T, error) {
+	return retrierExec(ctx, r, false, func(context.Context, RetryAttempt) (T, error) {
+		return fn()
+	})
+}
+
+// RetrierDoAttempt executes the function returning a result using the retrier,
+// the function receives the attempt information
+func RetrierDoAttempt[T any](
+	ctx context.Context,
+	r *Retrier,
+	fn func(ctx context.Context, attempt RetryAttempt) (T, error),
+) (T, error) {
+	return retrierExec(ctx, r, true, fn)
+}
+
+// retrierExec is the retry loop used by all the retry functions.
+// The attempt timeout is only applied when the function uses the context it receives (fnUsesCtx).
+func retrierExec[T any](
+	ctx context.Context,
+	r *Retrier,
+	fnUsesCtx bool,
+	fn func(ctx context.Context, attempt RetryAttempt) (T, error),
+) (_ T, err error) {
+	cfg := &r.cfg
+	retry := 0
+	// Number of attempts actually executed, attempts rejected by the circuit breaker are not counted
+	executed := 0
+	if cfg.onGiveUp != nil {
+		defer func() {
+			if err != nil {
+				cfg.onGiveUp(executed, err)
+			}
+		}()
+	}
+
+	startTime := time.Now()
+	nextDelay := cfg.retryDelay(0, 0)
+	for {
+		attempt := RetryAttempt{Number: retry + 1, RemainingRetries: -1}
+		if r.maxRetries >= 0 {
+			attempt.RemainingRetries = r.maxRetries - retry
+		}
+		var v T
+		var stop, attemptTimedOut bool
+		stop, err = cfg.execAttempt(func() (err error) {
+			executed++
+			if !fnUsesCtx || cfg.attemptTimeout <= 0 {
+				v, err = fn(ctx, attempt)
+				return err
+			}
+			attemptCtx, cancel := context.WithTimeout(ctx, cfg.attemptTimeout)
+			defer cancel()
+			v, err = fn(attemptCtx, attempt)
+			attemptTimedOut = errors.Is(err, context.DeadlineExceeded) &&
+				errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+			return err
+		})
+		if err == nil {
+			return v, nil
+		}
+		if stop || attempt.IsLast() {
+			return v, err
+		}
+		if !attemptTimedOut && cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
+			return v, err
+		}
+		// The delay hinted by the error takes precedence over the computed one
+		sleepDelay := cfg.retryAfterDelay(err, nextDelay)
+		if cfg.maxElapsedTime > 0 && time.Since(startTime)+sleepDelay > cfg.maxElapsedTime {
+			return v, err
+		}
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt.Number, err, sleepDelay)
+		}
+		timer := time.NewTimer(sleepDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, ctx.Err() // nolint: wrapcheck
+		case <-timer.C:
+		}
+		retry++
+		nextDelay = cfg.retryDelay(retry, nextDelay)
+	}
+}
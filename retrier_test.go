@@ -0,0 +1,231 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_Retrier(t *testing.T) {
+	errTest := errors.New("test error")
+	errFatal := errors.New("fatal error")
+
+	t.Run("Do", func(t *testing.T) {
+		r := NewRetrier(3, time.Millisecond, ExecRetryIfErrorIsNot(errFatal))
+		attempts := 0
+		assert.Nil(t, r.Do(func() error {
+			attempts++
+			if attempts < 3 {
+				return errTest
+			}
+			return nil
+		}))
+		assert.Equal(t, 3, attempts)
+
+		attempts = 0
+		assert.ErrorIs(t, r.Do(func() error {
+			attempts++
+			return errTest
+		}), errTest)
+		assert.Equal(t, 4, attempts)
+
+		attempts = 0
+		assert.ErrorIs(t, r.Do(func() error {
+			attempts++
+			return errFatal
+		}), errFatal)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("DoCtx", func(t *testing.T) {
+		r := NewRetrier(-1, 10*time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 25*time.Millisecond)
+		defer cancel()
+		attempts := 0
+		err := r.DoCtx(ctx, func() error {
+			attempts++
+			return errTest
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("RetrierDo / RetrierDoCtx", func(t *testing.T) {
+		r := NewRetrier(2, time.Millisecond)
+		attempts := 0
+		v, err := RetrierDo(r, func() (int, error) {
+			attempts++
+			if attempts < 2 {
+				return 0, errTest
+			}
+			return 10, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 10, v)
+
+		s, err := RetrierDoCtx(context.Background(), r, func() (string, error) {
+			return "last", errTest
+		})
+		assert.ErrorIs(t, err, errTest)
+		assert.Equal(t, "last", s)
+	})
+
+	t.Run("shared between goroutines", func(t *testing.T) {
+		r := NewRetrier(5, time.Millisecond, ExecRetryDelayExpoBackoff(time.Millisecond))
+		var total atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				attempts := 0
+				assert.Nil(t, r.Do(func() error {
+					total.Add(1)
+					attempts++
+					if attempts < 3 {
+						return errTest
+					}
+					return nil
+				}))
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(30), total.Load())
+	})
+	t.Run("callbacks", func(t *testing.T) {
+		type retryEvent struct {
+			attempt   int
+			nextDelay time.Duration
+		}
+		var events []retryEvent
+		giveUpAttempts := 0
+		var giveUpErr error
+		r := NewRetrier(2, time.Millisecond, ExecRetryDelayIncr(time.Millisecond),
+			ExecRetryOnRetry(func(attempt int, err error, nextDelay time.Duration) {
+				assert.ErrorIs(t, err, errTest)
+				events = append(events, retryEvent{attempt, nextDelay})
+			}),
+			ExecRetryOnGiveUp(func(attempts int, lastErr error) {
+				giveUpAttempts = attempts
+				giveUpErr = lastErr
+			}))
+
+		assert.ErrorIs(t, r.Do(func() error { return errTest }), errTest)
+		assert.Equal(t, []retryEvent{{1, time.Millisecond}, {2, 2 * time.Millisecond}}, events)
+		assert.Equal(t, 3, giveUpAttempts)
+		assert.ErrorIs(t, giveUpErr, errTest)
+
+		// No give up on success
+		events, giveUpAttempts = nil, 0
+		attempts := 0
+		assert.Nil(t, r.Do(func() error {
+			attempts++
+			if attempts == 1 {
+				return errTest
+			}
+			return nil
+		}))
+		assert.Equal(t, 1, len(events))
+		assert.Equal(t, 0, giveUpAttempts)
+
+		// Options are shared with the ExecRetry functions
+		events = nil
+		_ = ExecRetry(func() error { return errTest }, 1, time.Millisecond,
+			ExecRetryOnRetry(func(attempt int, err error, nextDelay time.Duration) {
+				events = append(events, retryEvent{attempt, nextDelay})
+			}))
+		assert.Equal(t, []retryEvent{{1, time.Millisecond}}, events)
+	})
+
+	t.Run("DoAttempt / RetrierDoAttempt", func(t *testing.T) {
+		r := NewRetrier(2, time.Millisecond)
+		var attempts []RetryAttempt
+		err := r.DoAttempt(context.Background(), func(ctx context.Context, attempt RetryAttempt) error {
+			attempts = append(attempts, attempt)
+			return errTest
+		})
+		assert.ErrorIs(t, err, errTest)
+		assert.Equal(t, []RetryAttempt{{1, 2}, {2, 1}, {3, 0}}, attempts)
+		assert.True(t, attempts[2].IsLast())
+
+		endpoint, err := RetrierDoAttempt(context.Background(), NewRetrier(-1, time.Millisecond),
+			func(ctx context.Context, attempt RetryAttempt) (string, error) {
+				assert.Equal(t, -1, attempt.RemainingRetries)
+				if attempt.Number < 3 {
+					return "", errTest
+				}
+				return "fallback", nil
+			})
+		assert.Nil(t, err)
+		assert.Equal(t, "fallback", endpoint)
+	})
+	t.Run("max elapsed time", func(t *testing.T) {
+		attempts := 0
+		start := time.Now()
+		err := ExecRetry(func() error {
+			attempts++
+			return errTest
+		}, -1, 10*time.Millisecond, ExecRetryDelayExpoBackoff(0), ExecRetryMaxElapsedTime(50*time.Millisecond))
+		assert.ErrorIs(t, err, errTest)
+		// Sleeps 10ms, 20ms, then the next sleep of 40ms would exceed the budget
+		assert.Equal(t, 3, attempts)
+		assert.True(t, time.Since(start) < 50*time.Millisecond)
+	})
+
+	t.Run("attempt timeout", func(t *testing.T) {
+		r := NewRetrier(3, time.Millisecond, ExecRetryAttemptTimeout(10*time.Millisecond),
+			ExecRetryIfErrorIs(errTest))
+		v, err := RetrierDoAttempt(context.Background(), r, func(ctx context.Context, attempt RetryAttempt) (int, error) {
+			if attempt.Number < 3 {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			return attempt.Number, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, v)
+
+		// Timeout of the parent context is not retried
+		r = NewRetrier(3, time.Millisecond, ExecRetryAttemptTimeout(time.Second), ExecRetryIfErrorIs(errTest))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		attempts := 0
+		err = r.DoAttempt(ctx, func(ctx context.Context, attempt RetryAttempt) error {
+			attempts++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("retry-after hint", func(t *testing.T) {
+		var delays []time.Duration
+		attempts := 0
+		err := ExecRetry(func() error {
+			attempts++
+			switch attempts {
+			case 1:
+				return ErrWithRetryAfter(errTest, 2*time.Millisecond)
+			case 2:
+				return fmt.Errorf("wrapped: %w", ErrWithRetryAfter(errTest, time.Hour))
+			}
+			return errTest
+		}, 3, time.Millisecond, ExecRetryDelayIncr(time.Millisecond), ExecRetryDelayMax(5*time.Millisecond),
+			ExecRetryOnRetry(func(attempt int, err error, nextDelay time.Duration) {
+				delays = append(delays, nextDelay)
+			}))
+		assert.ErrorIs(t, err, errTest)
+		// The hints are used, capped by the max delay, then the computed delays are used again
+		assert.Equal(t, []time.Duration{2 * time.Millisecond, 5 * time.Millisecond, 3 * time.Millisecond}, delays)
+	})
+}
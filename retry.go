@@ -1,9 +1,10 @@
 package gofn
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math"
-	"math/rand"
 	"time"
 )
 
@@ -22,6 +23,48 @@ type ExecRetryConfig struct {
 	incremental      time.Duration
 	expBackoffJitter time.Duration
 	shouldRetry      func(error) bool
+	circuitBreaker   *CircuitBreaker
+	onRetry          func(attempt int, err error, nextDelay time.Duration)
+	onGiveUp         func(attempts int, lastErr error)
+	backoff          Backoff
+	randSource       RandSource
+	maxElapsedTime   time.Duration
+	attemptTimeout   time.Duration
+}
+
+func (cfg *ExecRetryConfig) rand() RandSource {
+	if cfg.randSource != nil {
+		return cfg.randSource
+	}
+	return globalRandSource{}
+}
+
+// retryAfterDelay returns the delay hinted by the error if there is one, capped by the max delay
+func (cfg *ExecRetryConfig) retryAfterDelay(err error, delay time.Duration) time.Duration {
+	var retryAfterErr RetryAfterError
+	if !errors.As(err, &retryAfterErr) {
+		return delay
+	}
+	delay = Max(retryAfterErr.RetryAfter(), 0)
+	if cfg.maxDelay > 0 && delay > cfg.maxDelay {
+		return cfg.maxDelay
+	}
+	return delay
+}
+
+// retryDelay returns the delay before the retry number `retry` (starting from 0)
+func (cfg *ExecRetryConfig) retryDelay(retry int, prevDelay time.Duration) time.Duration {
+	if cfg.backoff == nil {
+		if retry == 0 {
+			return cfg.delay
+		}
+		return cfg.nextDelay(retry)
+	}
+	delay := cfg.backoff.NextDelay(retry, prevDelay, cfg.rand())
+	if cfg.maxDelay > 0 && delay > cfg.maxDelay {
+		return cfg.maxDelay
+	}
+	return Max(delay, 0)
 }
 
 func (cfg *ExecRetryConfig) nextDelay(retry int) time.Duration {
@@ -43,7 +86,7 @@ func (cfg *ExecRetryConfig) nextDelay(retry int) time.Duration {
 	// Expo backoff
 	jitter := time.Duration(0)
 	if cfg.expBackoffJitter > 0 {
-		jitter = time.Duration(rand.Int63n(int64(cfg.expBackoffJitter))) //nolint:gosec
+		jitter = time.Duration(cfg.rand().Int63n(int64(cfg.expBackoffJitter)))
 	}
 
 	exp := 1.0
@@ -57,6 +100,31 @@ func (cfg *ExecRetryConfig) nextDelay(retry int) time.Duration {
 	return delay
 }
 
+// execAttempt executes an attempt through the circuit breaker if there is one.
+// Returns stop as true when the retry loop should stop because the circuit is open.
+func (cfg *ExecRetryConfig) execAttempt(fn func() error) (stop bool, err error) {
+	if cfg.circuitBreaker == nil {
+		return false, fn()
+	}
+	done, err := cfg.circuitBreaker.Allow()
+	if err != nil {
+		return true, err
+	}
+	// Like CircuitBreaker.Execute(), a panic is recorded as a failure, so a half-open probe slot is released
+	defer func() {
+		if r := recover(); r != nil {
+			done(newPanicError(r, -1))
+			panic(r)
+		}
+	}()
+	err = fn()
+	done(err)
+	if err != nil && cfg.circuitBreaker.State() == CircuitOpen {
+		return true, fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+	}
+	return false, err
+}
+
 type ExecRetryOption func(*ExecRetryConfig)
 
 func ExecRetryDelayMax(maxDelay time.Duration) ExecRetryOption {
@@ -111,37 +179,75 @@ func ExecRetryIfErrorIsNot(errs ...error) ExecRetryOption {
 	}
 }
 
+// ExecRetryCircuitBreaker makes every attempt go through the circuit breaker. The retry loop stops early
+// with an ErrCircuitOpen error when the circuit is open. The circuit breaker can be shared between calls.
+func ExecRetryCircuitBreaker(circuitBreaker *CircuitBreaker) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.circuitBreaker = circuitBreaker
+	}
+}
+
+// ExecRetryBackoff sets the backoff strategy calculating the delays between retries, such as BackoffFullJitter().
+// The delay passed to the retry function is ignored, ExecRetryDelayMax() still caps the delays.
+func ExecRetryBackoff(backoff Backoff) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.backoff = backoff
+	}
+}
+
+// ExecRetryRandSource sets the source of random numbers used for the delay jitter (default is the global
+// source of math/rand). This is useful to make the delays deterministic in tests.
+func ExecRetryRandSource(randSource RandSource) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.randSource = randSource
+	}
+}
+
+// ExecRetryMaxElapsedTime sets the total time budget of the retry loop. The loop stops and returns
+// the last error when the sleep before the next attempt would exceed the budget.
+func ExecRetryMaxElapsedTime(maxElapsedTime time.Duration) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.maxElapsedTime = maxElapsedTime
+	}
+}
+
+// ExecRetryAttemptTimeout gives every attempt its own context with the timeout, an attempt failing with
+// context.DeadlineExceeded because of this timeout is always retryable.
+// The option applies to the functions receiving a context: ExecRetryCtxFunc(), ExecRetryCtxFunc2(),
+// Retrier.DoAttempt() and RetrierDoAttempt(). It is ignored by the functions receiving no context,
+// such as ExecRetry() and ExecRetryCtx(), as they can't be interrupted.
+func ExecRetryAttemptTimeout(attemptTimeout time.Duration) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.attemptTimeout = attemptTimeout
+	}
+}
+
+// ExecRetryOnRetry sets a callback which is called every time an attempt fails and another attempt
+// is scheduled. `attempt` is the number of the failed attempt starting from 1.
+func ExecRetryOnRetry(onRetry func(attempt int, err error, nextDelay time.Duration)) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.onRetry = onRetry
+	}
+}
+
+// ExecRetryOnGiveUp sets a callback which is called when the retry loop stops with an error,
+// `lastErr` is the error returned to the caller. `attempts` is the number of attempts actually executed,
+// attempts rejected by the circuit breaker are not counted, so it can be 0.
+func ExecRetryOnGiveUp(onGiveUp func(attempts int, lastErr error)) ExecRetryOption {
+	return func(config *ExecRetryConfig) {
+		config.onGiveUp = onGiveUp
+	}
+}
+
+// ExecRetry executes the function until it succeeds or the retry budget is exhausted.
+// See NewRetrier() to build the settings once and share them.
 func ExecRetry(
 	fn func() error,
 	maxRetries int,
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) error {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		err := fn()
-		if err == nil {
-			return nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return err
-		}
-		time.Sleep(nextDelay)
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	return NewRetrier(maxRetries, delay, options...).Do(fn)
 }
 
 func ExecRetry2[T any](
@@ -150,31 +256,7 @@ func ExecRetry2[T any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v, err := fn()
-		if err == nil {
-			return v, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v, err
-		}
-		time.Sleep(nextDelay)
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	return RetrierDo(NewRetrier(maxRetries, delay, options...), fn)
 }
 
 func ExecRetry3[T1, T2 any](
@@ -183,31 +265,7 @@ func ExecRetry3[T1, T2 any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T1, T2, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v1, v2, err := fn()
-		if err == nil {
-			return v1, v2, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v1, v2, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v1, v2, err
-		}
-		time.Sleep(nextDelay)
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	return ExecRetryCtx3(context.Background(), fn, maxRetries, delay, options...)
 }
 
 func ExecRetry4[T1, T2, T3 any](
@@ -216,31 +274,7 @@ func ExecRetry4[T1, T2, T3 any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T1, T2, T3, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v1, v2, v3, err := fn()
-		if err == nil {
-			return v1, v2, v3, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v1, v2, v3, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v1, v2, v3, err
-		}
-		time.Sleep(nextDelay)
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	return ExecRetryCtx4(context.Background(), fn, maxRetries, delay, options...)
 }
 
 func ExecRetry5[T1, T2, T3, T4 any](
@@ -249,29 +283,5 @@ func ExecRetry5[T1, T2, T3, T4 any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T1, T2, T3, T4, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v1, v2, v3, v4, err := fn()
-		if err == nil {
-			return v1, v2, v3, v4, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v1, v2, v3, v4, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v1, v2, v3, v4, err
-		}
-		time.Sleep(nextDelay)
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	return ExecRetryCtx5(context.Background(), fn, maxRetries, delay, options...)
 }
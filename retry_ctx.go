@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+// ExecRetryCtx executes the function until it succeeds or the retry budget is exhausted.
+// Returns the context error if the context is done while waiting between attempts.
 func ExecRetryCtx(
 	ctx context.Context,
 	fn func() error,
@@ -12,35 +14,37 @@ func ExecRetryCtx(
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) error {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
+	return NewRetrier(maxRetries, delay, options...).DoCtx(ctx, fn)
+}
 
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		err := fn()
-		if err == nil {
-			return nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return err
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err() // nolint: wrapcheck
-		case <-time.After(nextDelay):
-		}
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+// ExecRetryCtxFunc executes the function until it succeeds or the retry budget is exhausted.
+// The function receives the context of the attempt, which has a timeout when ExecRetryAttemptTimeout() is set.
+func ExecRetryCtxFunc(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+	maxRetries int,
+	delay time.Duration,
+	options ...ExecRetryOption,
+) error {
+	return NewRetrier(maxRetries, delay, options...).DoAttempt(ctx,
+		func(ctx context.Context, _ RetryAttempt) error {
+			return fn(ctx)
+		})
+}
+
+// ExecRetryCtxFunc2 executes the function returning a result until it succeeds or the retry budget
+// is exhausted. The function receives the context of the attempt, see ExecRetryCtxFunc().
+func ExecRetryCtxFunc2[T any](
+	ctx context.Context,
+	fn func(ctx context.Context) (T, error),
+	maxRetries int,
+	delay time.Duration,
+	options ...ExecRetryOption,
+) (T, error) {
+	return RetrierDoAttempt(ctx, NewRetrier(maxRetries, delay, options...),
+		func(ctx context.Context, _ RetryAttempt) (T, error) {
+			return fn(ctx)
+		})
 }
 
 func ExecRetryCtx2[T any](
@@ -50,35 +54,7 @@ func ExecRetryCtx2[T any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v, err := fn()
-		if err == nil {
-			return v, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v, err
-		}
-		select {
-		case <-ctx.Done():
-			return v, ctx.Err() // nolint: wrapcheck
-		case <-time.After(nextDelay):
-		}
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	return RetrierDoCtx(ctx, NewRetrier(maxRetries, delay, options...), fn)
 }
 
 func ExecRetryCtx3[T1, T2 any](
@@ -88,35 +64,11 @@ func ExecRetryCtx3[T1, T2 any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T1, T2, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v1, v2, err := fn()
-		if err == nil {
-			return v1, v2, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v1, v2, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v1, v2, err
-		}
-		select {
-		case <-ctx.Done():
-			return v1, v2, ctx.Err() // nolint: wrapcheck
-		case <-time.After(nextDelay):
-		}
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	v, err := RetrierDoCtx(ctx, NewRetrier(maxRetries, delay, options...), func() (t Tuple2[T1, T2], err error) {
+		t.Elem1, t.Elem2, err = fn()
+		return t, err
+	})
+	return v.Elem1, v.Elem2, err
 }
 
 func ExecRetryCtx4[T1, T2, T3 any](
@@ -126,35 +78,11 @@ func ExecRetryCtx4[T1, T2, T3 any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T1, T2, T3, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v1, v2, v3, err := fn()
-		if err == nil {
-			return v1, v2, v3, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v1, v2, v3, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v1, v2, v3, err
-		}
-		select {
-		case <-ctx.Done():
-			return v1, v2, v3, ctx.Err() // nolint: wrapcheck
-		case <-time.After(nextDelay):
-		}
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	v, err := RetrierDoCtx(ctx, NewRetrier(maxRetries, delay, options...), func() (t Tuple3[T1, T2, T3], err error) {
+		t.Elem1, t.Elem2, t.Elem3, err = fn()
+		return t, err
+	})
+	return v.Elem1, v.Elem2, v.Elem3, err
 }
 
 func ExecRetryCtx5[T1, T2, T3, T4 any](
@@ -164,33 +92,10 @@ func ExecRetryCtx5[T1, T2, T3, T4 any](
 	delay time.Duration,
 	options ...ExecRetryOption,
 ) (T1, T2, T3, T4, error) {
-	cfg := &ExecRetryConfig{
-		kind:  execRetryFixedDelay,
-		delay: delay,
-	}
-	for _, option := range options {
-		option(cfg)
-	}
-
-	retry := 0
-	nextDelay := cfg.delay
-	for {
-		v1, v2, v3, v4, err := fn()
-		if err == nil {
-			return v1, v2, v3, v4, nil
-		}
-		if maxRetries >= 0 && retry >= maxRetries {
-			return v1, v2, v3, v4, err
-		}
-		if cfg.shouldRetry != nil && !cfg.shouldRetry(err) {
-			return v1, v2, v3, v4, err
-		}
-		select {
-		case <-ctx.Done():
-			return v1, v2, v3, v4, ctx.Err() // nolint: wrapcheck
-		case <-time.After(nextDelay):
-		}
-		retry++
-		nextDelay = cfg.nextDelay(retry)
-	}
+	v, err := RetrierDoCtx(ctx, NewRetrier(maxRetries, delay, options...),
+		func() (t Tuple4[T1, T2, T3, T4], err error) {
+			t.Elem1, t.Elem2, t.Elem3, t.Elem4, err = fn()
+			return t, err
+		})
+	return v.Elem1, v.Elem2, v.Elem3, v.Elem4, err
 }
@@ -363,3 +363,39 @@ func TestExecRetryCtx5(t *testing.T) {
 	assert.Equal(t, 2.3, v4)
 	assert.Equal(t, 2, count)
 }
+
+func TestExecRetryCtxFunc(t *testing.T) {
+	errTest := errors.New("test error")
+	ctx := context.Background()
+
+	// Every attempt gets its own timeout, timed out attempts are retried
+	start := time.Now()
+	count := 0
+	err := ExecRetryCtxFunc(ctx, func(ctx context.Context) error {
+		count++
+		if count < 3 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}, 3, time.Nanosecond, ExecRetryAttemptTimeout(10*time.Millisecond), ExecRetryIfErrorIs(errTest))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+
+	v, err := ExecRetryCtxFunc2(ctx, func(ctx context.Context) (int, error) {
+		time.Sleep(30 * time.Millisecond)
+		return 1, ctx.Err()
+	}, 2, time.Nanosecond, ExecRetryAttemptTimeout(10*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, v)
+
+	// The attempt timeout is ignored by the functions receiving no context
+	count = 0
+	err = ExecRetryCtx(ctx, func() error {
+		count++
+		return context.DeadlineExceeded
+	}, 3, time.Nanosecond, ExecRetryAttemptTimeout(10*time.Millisecond), ExecRetryIfErrorIs(errTest))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,115 @@
+package gofn
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Semaphore is a weighted semaphore. Waiters are served in FIFO order, a waiter requesting
+// a big weight blocks the waiters behind it until it is served.
+type Semaphore struct {
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+	waiters list.List
+}
+
+// NewSemaphore creates a weighted semaphore with the given maximum combined weight
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{size: size}
+}
+
+// Acquire acquires the semaphore with a weight of n, blocking until resources are available
+// or the context is done. On failure, the semaphore is left unchanged.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	done := ctx.Done()
+
+	s.mu.Lock()
+	select {
+	case <-done:
+		s.mu.Unlock()
+		return ctx.Err() // nolint: wrapcheck
+	default:
+	}
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		s.mu.Unlock()
+		return ErrWrap(ErrOverflow, "weight exceeds semaphore size")
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semaphoreWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-done:
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// Acquired right after the context is done, give it back
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// The waiters behind may be able to proceed now
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err() // nolint: wrapcheck
+	}
+}
+
+// TryAcquire acquires the semaphore with a weight of n without blocking, returns false on failure
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release releases the semaphore with a weight of n
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// notifyWaiters wakes up the waiters in FIFO order, must be called with the lock held
+func (s *Semaphore) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(semaphoreWaiter) // nolint: forcetypeassert
+		if s.size-s.cur < w.n {
+			// Not enough resources for the next waiter, keep the order by not serving the ones behind
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
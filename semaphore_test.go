@@ -0,0 +1,96 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_Semaphore(t *testing.T) {
+	t.Run("acquire and release", func(t *testing.T) {
+		sem := NewSemaphore(5)
+		assert.Nil(t, sem.Acquire(context.Background(), 3))
+		assert.True(t, sem.TryAcquire(2))
+		assert.False(t, sem.TryAcquire(1))
+		sem.Release(3)
+		assert.True(t, sem.TryAcquire(3))
+		sem.Release(5)
+	})
+
+	t.Run("weight exceeds size", func(t *testing.T) {
+		sem := NewSemaphore(2)
+		assert.ErrorIs(t, sem.Acquire(context.Background(), 3), ErrOverflow)
+		assert.False(t, sem.TryAcquire(3))
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		sem := NewSemaphore(2)
+		assert.True(t, sem.TryAcquire(2))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, sem.Acquire(ctx, 1), context.DeadlineExceeded)
+		assert.ErrorIs(t, sem.Acquire(ctx, 1), context.DeadlineExceeded)
+
+		sem.Release(2)
+		assert.True(t, sem.TryAcquire(2))
+	})
+
+	t.Run("fifo order", func(t *testing.T) {
+		sem := NewSemaphore(4)
+		assert.True(t, sem.TryAcquire(4))
+
+		var mu sync.Mutex
+		var order []int
+		var wg sync.WaitGroup
+		for i, n := range []int64{3, 2, 3} {
+			wg.Add(1)
+			go func(i int, n int64) {
+				defer wg.Done()
+				assert.Nil(t, sem.Acquire(context.Background(), n))
+				mu.Lock()
+				order = append(order, i)
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				sem.Release(n)
+			}(i, n)
+			time.Sleep(5 * time.Millisecond) // make sure the waiters queue in order
+		}
+		sem.Release(4)
+		wg.Wait()
+		// The last waiter can't be served together with the 2nd one, so the order is strict
+		assert.Equal(t, []int{0, 1, 2}, order)
+	})
+
+	t.Run("canceled front waiter unblocks the others", func(t *testing.T) {
+		sem := NewSemaphore(3)
+		assert.True(t, sem.TryAcquire(2))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- sem.Acquire(ctx, 3)
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		acquiredChan := make(chan struct{})
+		go func() {
+			_ = sem.Acquire(context.Background(), 1)
+			close(acquiredChan)
+		}()
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-errChan, context.Canceled)
+		<-acquiredChan
+		sem.Release(3)
+	})
+
+	t.Run("release more than held", func(t *testing.T) {
+		sem := NewSemaphore(1)
+		assert.Panics(t, func() { sem.Release(1) })
+	})
+}
@@ -0,0 +1,90 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+)
+
+type singleFlightCall[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+	dups int
+}
+
+// SingleFlight deduplicates concurrent calls having the same key.
+// While a call for a key is in-flight, other callers for the key wait for it and receive the same result.
+// The zero value is ready to use.
+type SingleFlight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*singleFlightCall[V]
+}
+
+func (g *SingleFlight[K, V]) start(key K, fn func() (V, error)) (*singleFlightCall[V], bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[K]*singleFlightCall[V]{}
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		return c, true
+	}
+	c := &singleFlightCall[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	// The call runs in its own goroutine, so it is not affected by any individual caller
+	go g.run(key, c, fn)
+	return c, false
+}
+
+func (g *SingleFlight[K, V]) run(key K, c *singleFlightCall[V], fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = newPanicError(r, -1)
+		}
+		g.mu.Lock()
+		// The key may be forgotten and taken by another call
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+}
+
+// Do executes the function for the key if there is no in-flight call for the key, otherwise waits for
+// the in-flight call to finish. All callers receive the same result. The returned bool tells whether
+// the result is shared with other callers. A panic in the function is returned as ErrPanic to all callers.
+func (g *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (V, error, bool) { // nolint: revive
+	c, shared := g.start(key, fn)
+	<-c.done
+	return c.val, c.err, shared || c.dups > 0
+}
+
+// DoCtx is similar to Do, but the caller can give up waiting when the context is done.
+// Giving up does not cancel the in-flight call, the other callers still receive its result.
+func (g *SingleFlight[K, V]) DoCtx( // nolint: revive
+	ctx context.Context,
+	key K,
+	fn func() (V, error),
+) (V, error, bool) {
+	c, shared := g.start(key, fn)
+	select {
+	case <-c.done:
+		return c.val, c.err, shared || c.dups > 0
+	case <-ctx.Done():
+		var zeroV V
+		return zeroV, ctx.Err(), shared // nolint: wrapcheck
+	}
+}
+
+// Forget forgets the in-flight call for the key, so the next call for the key executes the function
+// instead of waiting for the in-flight one
+func (g *SingleFlight[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
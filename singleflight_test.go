@@ -0,0 +1,130 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_SingleFlight(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("concurrent calls share the result", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		var callCount atomic.Int32
+		fn := func() (int, error) {
+			callCount.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return 123, nil
+		}
+
+		var wg sync.WaitGroup
+		var sharedCount atomic.Int32
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err, shared := g.Do("key", fn)
+				assert.Nil(t, err)
+				assert.Equal(t, 123, v)
+				if shared {
+					sharedCount.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), callCount.Load())
+		assert.Equal(t, int32(10), sharedCount.Load())
+
+		// Next call after the previous one finishes executes the function again
+		v, err, shared := g.Do("key", fn)
+		assert.Nil(t, err)
+		assert.Equal(t, 123, v)
+		assert.False(t, shared)
+		assert.Equal(t, int32(2), callCount.Load())
+	})
+
+	t.Run("different keys", func(t *testing.T) {
+		var g SingleFlight[int, int]
+		v1, _, _ := g.Do(1, func() (int, error) { return 1, nil })
+		v2, err, _ := g.Do(2, func() (int, error) { return 2, errTest })
+		assert.Equal(t, 1, v1)
+		assert.Equal(t, 2, v2)
+		assert.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("panic is returned to all callers", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err, _ := g.Do("key", func() (int, error) {
+					time.Sleep(20 * time.Millisecond)
+					panic(errTest)
+				})
+				assert.ErrorIs(t, err, ErrPanic)
+				assert.ErrorIs(t, err, errTest)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("waiter gives up without killing the call", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		fn := func() (int, error) {
+			time.Sleep(50 * time.Millisecond)
+			return 123, nil
+		}
+
+		resultChan := make(chan int, 1)
+		go func() {
+			v, _, _ := g.Do("key", fn)
+			resultChan <- v
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		v, err, shared := g.DoCtx(ctx, "key", fn)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, v)
+		assert.True(t, shared)
+
+		assert.Equal(t, 123, <-resultChan)
+
+		v, err, _ = g.DoCtx(context.Background(), "key", fn)
+		assert.Nil(t, err)
+		assert.Equal(t, 123, v)
+	})
+
+	t.Run("forget", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		var callCount atomic.Int32
+		fn := func() (int, error) {
+			n := callCount.Add(1)
+			time.Sleep(30 * time.Millisecond)
+			return int(n), nil
+		}
+
+		resultChan := make(chan int, 1)
+		go func() {
+			v, _, _ := g.Do("key", fn)
+			resultChan <- v
+		}()
+		time.Sleep(5 * time.Millisecond)
+		g.Forget("key")
+
+		v, _, shared := g.Do("key", fn)
+		assert.Equal(t, 2, v)
+		assert.False(t, shared)
+		assert.Equal(t, 1, <-resultChan)
+	})
+}
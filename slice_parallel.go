@@ -0,0 +1,175 @@
+package gofn
+
+import (
+	"context"
+	"runtime"
+)
+
+// parallelChunks splits the slice into chunks for parallel processing, returns the chunks and their offsets
+func parallelChunks[T any, S ~[]T](s S, workers int) ([]S, []int) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunks := ChunkByPieces(s, workers)
+	offsets := make([]int, len(chunks))
+	offset := 0
+	for i := range chunks {
+		offsets[i] = offset
+		offset += len(chunks[i])
+	}
+	return chunks, offsets
+}
+
+// execParallel executes the chunk tasks and waits for all of them to exit before returning
+func execParallel[R any](ctx context.Context, tasks []func(ctx context.Context) (R, error)) ([]R, error) {
+	cfg := &ExecTasksConfig{stopOnError: true, waitOnStop: true}
+	results, errMap := execTasks(ctx, cfg, tasks)
+	for _, err := range errMap {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ParallelMapSlice transforms a slice to another with map function using multiple goroutines.
+// The slice is split into `workers` chunks (pass 0 to use GOMAXPROCS), the result keeps the order
+// of the input. A panic in the map function is returned as ErrPanic.
+func ParallelMapSlice[T any, U any, S ~[]T](
+	ctx context.Context,
+	s S,
+	workers int,
+	mapFunc func(T) U,
+) ([]U, error) {
+	result := make([]U, len(s))
+	chunks, offsets := parallelChunks(s, workers)
+	tasks := make([]func(ctx context.Context) (struct{}, error), len(chunks))
+	for i := range chunks {
+		chunk, offset := chunks[i], offsets[i]
+		tasks[i] = func(ctx context.Context) (struct{}, error) {
+			done := ctx.Done()
+			for j := range chunk {
+				select {
+				case <-done:
+					return struct{}{}, ctx.Err() // nolint: wrapcheck
+				default:
+				}
+				result[offset+j] = mapFunc(chunk[j])
+			}
+			return struct{}{}, nil
+		}
+	}
+	if _, err := execParallel(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParallelFilter filters slice elements with condition using multiple goroutines.
+// The slice is split into `workers` chunks (pass 0 to use GOMAXPROCS), the result keeps the order
+// of the input. A panic in the filter function is returned as ErrPanic.
+func ParallelFilter[T any, S ~[]T](
+	ctx context.Context,
+	s S,
+	workers int,
+	filterFunc func(t T) bool,
+) (S, error) {
+	chunks, _ := parallelChunks(s, workers)
+	tasks := make([]func(ctx context.Context) (S, error), len(chunks))
+	for i := range chunks {
+		chunk := chunks[i]
+		tasks[i] = func(ctx context.Context) (S, error) {
+			done := ctx.Done()
+			result := make(S, 0, len(chunk))
+			for j := range chunk {
+				select {
+				case <-done:
+					return nil, ctx.Err() // nolint: wrapcheck
+				default:
+				}
+				if filterFunc(chunk[j]) {
+					result = append(result, chunk[j])
+				}
+			}
+			return result, nil
+		}
+	}
+	chunkResults, err := execParallel(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return Concat(chunkResults...), nil
+}
+
+// ParallelReduce reduces a slice to a value using multiple goroutines.
+// Every chunk is reduced separately, then the chunk results are combined using the reduce function
+// in the order of the chunks. Hence, the reduce function must be associative.
+func ParallelReduce[T any, S ~[]T](
+	ctx context.Context,
+	s S,
+	workers int,
+	reduceFunc func(accumulator, currentValue T) T,
+) (T, error) {
+	chunks, _ := parallelChunks(s, workers)
+	tasks := make([]func(ctx context.Context) (T, error), len(chunks))
+	for i := range chunks {
+		chunk := chunks[i]
+		tasks[i] = func(ctx context.Context) (T, error) {
+			done := ctx.Done()
+			accumulator := chunk[0]
+			for j := 1; j < len(chunk); j++ {
+				select {
+				case <-done:
+					return accumulator, ctx.Err() // nolint: wrapcheck
+				default:
+				}
+				accumulator = reduceFunc(accumulator, chunk[j])
+			}
+			return accumulator, nil
+		}
+	}
+	chunkResults, err := execParallel(ctx, tasks)
+	if err != nil {
+		var zeroT T
+		return zeroT, err
+	}
+	return Reduce(chunkResults, reduceFunc), nil
+}
+
+// ParallelReduceEx reduces a slice to a value with custom initial value using multiple goroutines.
+// Every chunk is reduced separately starting from `initVal`, then the chunk results are combined
+// using the combine function in the order of the chunks. Hence, `initVal` should be the identity
+// value of the combine function (e.g. 0 for sum, 1 for product), and the combine function must be
+// associative.
+func ParallelReduceEx[T any, U any, S ~[]T](
+	ctx context.Context,
+	s S,
+	workers int,
+	reduceFunc func(accumulator U, currentValue T, currentIndex int) U,
+	initVal U,
+	combineFunc func(U, U) U,
+) (U, error) {
+	chunks, offsets := parallelChunks(s, workers)
+	tasks := make([]func(ctx context.Context) (U, error), len(chunks))
+	for i := range chunks {
+		chunk, offset := chunks[i], offsets[i]
+		tasks[i] = func(ctx context.Context) (U, error) {
+			done := ctx.Done()
+			accumulator := initVal
+			for j := range chunk {
+				select {
+				case <-done:
+					return accumulator, ctx.Err() // nolint: wrapcheck
+				default:
+				}
+				accumulator = reduceFunc(accumulator, chunk[j], offset+j)
+			}
+			return accumulator, nil
+		}
+	}
+	chunkResults, err := execParallel(ctx, tasks)
+	if err != nil {
+		return initVal, err
+	}
+	return ReduceEx(chunkResults, func(accumulator U, currentValue U, _ int) U {
+		return combineFunc(accumulator, currentValue)
+	}, initVal), nil
+}
@@ -0,0 +1,121 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParallelMapSlice(t *testing.T) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+
+	result, err := ParallelMapSlice(context.Background(), s, 4, func(v int) string { return fmt.Sprintf("%d", v) })
+	assert.Nil(t, err)
+	assert.Equal(t, MapSlice(s, func(v int) string { return fmt.Sprintf("%d", v) }), result)
+
+	// Empty slice and default number of workers
+	result, err = ParallelMapSlice(context.Background(), []int{}, 0, func(v int) string { return "" })
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, result)
+
+	// More workers than items
+	result2, err := ParallelMapSlice(context.Background(), []int{1, 2, 3}, 10, func(v int) int { return v * 2 })
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 4, 6}, result2)
+
+	// Context canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = ParallelMapSlice(ctx, s, 2, func(v int) int {
+		time.Sleep(time.Millisecond)
+		return v
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Panic in map function
+	_, err = ParallelMapSlice(context.Background(), s, 2, func(v int) int {
+		if v == 999 {
+			panic(errors.New("panic"))
+		}
+		return v
+	})
+	assert.ErrorIs(t, err, ErrPanic)
+}
+
+func Test_ParallelFilter(t *testing.T) {
+	s := make([]int, 1001)
+	for i := range s {
+		s[i] = i
+	}
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	result, err := ParallelFilter(context.Background(), s, 3, isEven)
+	assert.Nil(t, err)
+	assert.Equal(t, Filter(s, isEven), result)
+
+	result, err = ParallelFilter(context.Background(), []int{}, 0, isEven)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ParallelFilter(ctx, s, 3, isEven)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_ParallelReduce(t *testing.T) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i + 1
+	}
+	sum := func(acc, v int) int { return acc + v }
+
+	result, err := ParallelReduce(context.Background(), s, 4, sum)
+	assert.Nil(t, err)
+	assert.Equal(t, 500500, result)
+
+	result, err = ParallelReduce(context.Background(), []int{}, 4, sum)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result)
+
+	// String concatenation is associative, the order is kept
+	strResult, err := ParallelReduce(context.Background(), []string{"a", "b", "c", "d", "e"}, 2,
+		func(acc, v string) string { return acc + v })
+	assert.Nil(t, err)
+	assert.Equal(t, "abcde", strResult)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ParallelReduce(ctx, s, 4, sum)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_ParallelReduceEx(t *testing.T) {
+	s := []string{"a", "b", "c", "d", "e"}
+
+	result, err := ParallelReduceEx(context.Background(), s, 2,
+		func(acc string, v string, i int) string { return acc + fmt.Sprintf("%s%d", v, i) },
+		"", func(a, b string) string { return a + b })
+	assert.Nil(t, err)
+	assert.Equal(t, "a0b1c2d3e4", result)
+
+	length, err := ParallelReduceEx(context.Background(), s, 0,
+		func(acc int, v string, i int) int { return acc + len(v) },
+		0, func(a, b int) int { return a + b })
+	assert.Nil(t, err)
+	assert.Equal(t, 5, length)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ParallelReduceEx(ctx, s, 2,
+		func(acc int, v string, i int) int { return acc + len(v) },
+		0, func(a, b int) int { return a + b })
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,196 @@
+package gofn
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type SupervisorConfig struct {
+	restartDelay   time.Duration
+	restartOptions []ExecRetryOption
+	maxRestarts    int
+	restartPeriod  time.Duration
+	errorHandler   func(name string, err error)
+	onGiveUp       func(name string, err error)
+}
+
+type SupervisorOption func(*SupervisorConfig)
+
+// SupervisorRestartDelay sets the delay before restarting a failed worker (default is 1 second).
+// The delay strategies of ExecRetry() can be used, such as ExecRetryDelayIncr(), ExecRetryDelayExpoBackoff()
+// and ExecRetryDelayMax(). ExecRetryCheck() and alike can be used to restart only on specific errors.
+// The delay grows with the number of restarts counted for the restart intensity.
+func SupervisorRestartDelay(delay time.Duration, options ...ExecRetryOption) SupervisorOption {
+	return func(config *SupervisorConfig) {
+		config.restartDelay = delay
+		config.restartOptions = options
+	}
+}
+
+// SupervisorRestartIntensity sets the restart-intensity limit: a worker is given up when it fails after
+// being restarted maxRestarts times within the period (pass period 0 to count all the restarts).
+// There is no limit by default.
+func SupervisorRestartIntensity(maxRestarts int, period time.Duration) SupervisorOption {
+	return func(config *SupervisorConfig) {
+		config.maxRestarts = maxRestarts
+		config.restartPeriod = period
+	}
+}
+
+// SupervisorErrorHandler sets a handler to receive the errors of the workers.
+// NOTE: the handler is called from the worker goroutines, it must be safe for concurrent use.
+func SupervisorErrorHandler(errorHandler func(name string, err error)) SupervisorOption {
+	return func(config *SupervisorConfig) {
+		config.errorHandler = errorHandler
+	}
+}
+
+// SupervisorOnGiveUp sets a callback which is called with the last error when a worker is given up.
+// NOTE: the callback is called from the worker goroutines, it must be safe for concurrent use.
+func SupervisorOnGiveUp(onGiveUp func(name string, err error)) SupervisorOption {
+	return func(config *SupervisorConfig) {
+		config.onGiveUp = onGiveUp
+	}
+}
+
+// Supervisor runs named long-lived workers and restarts them when they fail.
+// A worker returning nil is considered done and is not restarted.
+type Supervisor struct {
+	cfg     *SupervisorConfig
+	retrier *Retrier
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	stopped bool
+	workers map[string]struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSupervisor creates a new supervisor.
+// Call Stop() to stop the workers when the supervisor is no longer used.
+func NewSupervisor(options ...SupervisorOption) *Supervisor {
+	cfg := &SupervisorConfig{
+		restartDelay: time.Second,
+		maxRestarts:  -1,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Supervisor{
+		cfg:     cfg,
+		retrier: NewRetrier(-1, cfg.restartDelay, cfg.restartOptions...),
+		ctx:     ctx,
+		cancel:  cancel,
+		workers: map[string]struct{}{},
+	}
+}
+
+// Go starts a named worker. The context passed to the worker is canceled when the supervisor stops.
+// Returns ErrSupervisorDuplicate if a worker having the same name is running,
+// returns ErrSupervisorStopped if the supervisor is stopped.
+func (s *Supervisor) Go(name string, worker func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrSupervisorStopped
+	}
+	if _, exists := s.workers[name]; exists {
+		return fmt.Errorf("%w: %q", ErrSupervisorDuplicate, name)
+	}
+	s.workers[name] = struct{}{}
+	s.wg.Add(1)
+	go s.supervise(name, worker)
+	return nil
+}
+
+// Workers returns the names of the running workers
+func (s *Supervisor) Workers() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return MapKeys(s.workers)
+}
+
+// Stop cancels the context of the workers and waits for them to exit.
+// Returns the context error if the context is done first, the workers keep exiting in background.
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err() // nolint: wrapcheck
+	}
+}
+
+func (s *Supervisor) supervise(name string, worker func(ctx context.Context) error) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.workers, name)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	restartCount := 0
+	var restartDelay time.Duration
+	var restartTimes []time.Time
+	for {
+		err := s.runWorker(worker)
+		if err == nil || s.ctx.Err() != nil {
+			return
+		}
+		if s.cfg.errorHandler != nil {
+			s.cfg.errorHandler(name, err)
+		}
+
+		// Count the recent restarts
+		now := time.Now()
+		recentRestarts := restartCount
+		if s.cfg.restartPeriod > 0 {
+			restartTimes = FilterPtr(restartTimes, func(t *time.Time) bool {
+				return now.Sub(*t) < s.cfg.restartPeriod
+			})
+			recentRestarts = len(restartTimes)
+		}
+		if (s.retrier.cfg.shouldRetry != nil && !s.retrier.cfg.shouldRetry(err)) ||
+			(s.cfg.maxRestarts >= 0 && recentRestarts >= s.cfg.maxRestarts) {
+			if s.cfg.onGiveUp != nil {
+				s.cfg.onGiveUp(name, err)
+			}
+			return
+		}
+
+		restartDelay = s.retrier.cfg.retryDelay(recentRestarts, restartDelay)
+		timer := time.NewTimer(restartDelay)
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+		restartCount++
+		if s.cfg.restartPeriod > 0 {
+			restartTimes = append(restartTimes, now)
+		}
+	}
+}
+
+func (s *Supervisor) runWorker(worker func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r, -1)
+		}
+	}()
+	return worker(s.ctx)
+}
@@ -0,0 +1,126 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_Supervisor(t *testing.T) {
+	errTest := errors.New("test error")
+	errFatal := errors.New("fatal error")
+
+	t.Run("restart failed worker", func(t *testing.T) {
+		var runs atomic.Int32
+		var mu sync.Mutex
+		var errs []error
+		s := NewSupervisor(SupervisorRestartDelay(time.Millisecond),
+			SupervisorErrorHandler(func(name string, err error) {
+				assert.Equal(t, "worker", name)
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}))
+		assert.Nil(t, s.Go("worker", func(ctx context.Context) error {
+			switch runs.Add(1) {
+			case 1:
+				return errTest
+			case 2:
+				panic("boom")
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+		assert.ErrorIs(t, s.Go("worker", nil), ErrSupervisorDuplicate)
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, int32(3), runs.Load())
+		assert.Equal(t, []string{"worker"}, s.Workers())
+
+		assert.Nil(t, s.Stop(context.Background()))
+		assert.Equal(t, 2, len(errs))
+		assert.ErrorIs(t, errs[0], errTest)
+		assert.ErrorIs(t, errs[1], ErrPanic)
+		assert.Equal(t, 0, len(s.Workers()))
+		assert.ErrorIs(t, s.Go("worker2", nil), ErrSupervisorStopped)
+	})
+
+	t.Run("worker done", func(t *testing.T) {
+		var runs atomic.Int32
+		s := NewSupervisor()
+		assert.Nil(t, s.Go("worker", func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}))
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, 0, len(s.Workers()))
+		assert.Nil(t, s.Stop(context.Background()))
+		assert.Equal(t, int32(1), runs.Load())
+	})
+
+	t.Run("restart intensity", func(t *testing.T) {
+		var runs atomic.Int32
+		var giveUpErr atomic.Value
+		s := NewSupervisor(SupervisorRestartDelay(time.Millisecond, ExecRetryDelayIncr(time.Millisecond)),
+			SupervisorRestartIntensity(3, time.Second),
+			SupervisorOnGiveUp(func(name string, err error) {
+				giveUpErr.Store(err)
+			}))
+		assert.Nil(t, s.Go("worker", func(ctx context.Context) error {
+			runs.Add(1)
+			return errTest
+		}))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(4), runs.Load())
+		assert.ErrorIs(t, giveUpErr.Load().(error), errTest)
+		assert.Nil(t, s.Stop(context.Background()))
+	})
+
+	t.Run("restart intensity period", func(t *testing.T) {
+		var runs atomic.Int32
+		s := NewSupervisor(SupervisorRestartDelay(5*time.Millisecond), SupervisorRestartIntensity(1, time.Millisecond))
+		assert.Nil(t, s.Go("worker", func(ctx context.Context) error {
+			if runs.Add(1) > 5 {
+				<-ctx.Done()
+				return nil
+			}
+			return errTest
+		}))
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(6), runs.Load())
+		assert.Nil(t, s.Stop(context.Background()))
+	})
+
+	t.Run("give up on unrecoverable error", func(t *testing.T) {
+		var runs atomic.Int32
+		s := NewSupervisor(SupervisorRestartDelay(time.Millisecond, ExecRetryIfErrorIsNot(errFatal)))
+		assert.Nil(t, s.Go("worker", func(ctx context.Context) error {
+			if runs.Add(1) == 2 {
+				return errFatal
+			}
+			return errTest
+		}))
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(2), runs.Load())
+		assert.Nil(t, s.Stop(context.Background()))
+	})
+
+	t.Run("stop timed out", func(t *testing.T) {
+		s := NewSupervisor()
+		release := make(chan struct{})
+		assert.Nil(t, s.Go("worker", func(ctx context.Context) error {
+			<-release
+			return nil
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+		close(release)
+		assert.Nil(t, s.Stop(context.Background()))
+	})
+}
@@ -0,0 +1,230 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TaskGraphConfig struct {
+	cancelOnError bool
+}
+
+type TaskGraphOption func(*TaskGraphConfig)
+
+// TaskGraphCancelOnError sets whether to cancel the whole graph on the first failure (default is false).
+// By default, only the downstream tasks of a failed task are skipped, the other tasks still run.
+func TaskGraphCancelOnError(cancelOnError bool) TaskGraphOption {
+	return func(config *TaskGraphConfig) {
+		config.cancelOnError = cancelOnError
+	}
+}
+
+// TaskGraphResult result of a task executed in a graph
+type TaskGraphResult struct {
+	// Err error of the task, ErrTaskSkipped if the task is skipped
+	Err       error
+	StartTime time.Time
+	Duration  time.Duration
+}
+
+type taskGraphNode struct {
+	index int
+	name  string
+	task  func(ctx context.Context) error
+	deps  []string
+}
+
+// TaskGraph executes tasks having dependencies between them. A task starts only when all of its
+// dependencies succeed. Exec can be called multiple times, even concurrently, but Add must not be
+// called concurrently with other methods.
+type TaskGraph struct {
+	nodes map[string]*taskGraphNode
+	order []*taskGraphNode
+}
+
+// NewTaskGraph creates an empty task graph
+func NewTaskGraph() *TaskGraph {
+	return &TaskGraph{nodes: map[string]*taskGraphNode{}}
+}
+
+// Add adds a named task with its dependencies. Dependencies can be added later, but they must all be
+// added before the graph is executed.
+func (g *TaskGraph) Add(name string, task func(ctx context.Context) error, deps ...string) error {
+	if _, exists := g.nodes[name]; exists {
+		return fmt.Errorf("%w: %q", ErrTaskGraphDuplicate, name)
+	}
+	node := &taskGraphNode{index: len(g.order), name: name, task: task, deps: deps}
+	g.nodes[name] = node
+	g.order = append(g.order, node)
+	return nil
+}
+
+// Validate checks that all dependencies exist and there is no dependency cycle
+func (g *TaskGraph) Validate() error {
+	for _, node := range g.order {
+		for _, dep := range node.deps {
+			if _, exists := g.nodes[dep]; !exists {
+				return fmt.Errorf("%w: %q required by %q", ErrTaskGraphUnknownDep, dep, node.name)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int, len(g.nodes))
+	var path []string
+	var visit func(node *taskGraphNode) error
+	visit = func(node *taskGraphNode) error {
+		states[node.name] = visiting
+		path = append(path, node.name)
+		for _, dep := range node.deps {
+			switch states[dep] {
+			case visiting:
+				cycle := append(path[IndexOf(path, dep):], dep) // nolint: gocritic
+				return fmt.Errorf("%w: %s", ErrTaskGraphCycle, strings.Join(cycle, " -> "))
+			case unvisited:
+				if err := visit(g.nodes[dep]); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		states[node.name] = visited
+		return nil
+	}
+	for _, node := range g.order {
+		if states[node.name] == unvisited {
+			if err := visit(node); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Exec validates the graph and executes the tasks. Tasks whose dependencies are satisfied run
+// concurrently, maxConcurrentTasks limits the number of running tasks (pass 0 to set no limit).
+// Returns the results of all tasks by name, and the validation error or the errors of the failed
+// tasks joined. A panic in a task is returned as ErrPanic.
+// nolint: gocognit
+func (g *TaskGraph) Exec(
+	ctx context.Context,
+	maxConcurrentTasks uint,
+	options ...TaskGraphOption,
+) (map[string]*TaskGraphResult, error) {
+	cfg := &TaskGraphConfig{}
+	for _, option := range options {
+		option(cfg)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Graph state is local to every execution, so executions don't interfere with each other
+	pendingDeps := make(map[string]int, len(g.order))
+	dependents := make(map[string][]*taskGraphNode, len(g.order))
+	for _, node := range g.order {
+		pendingDeps[node.name] = len(node.deps)
+		for _, dep := range node.deps {
+			dependents[dep] = append(dependents[dep], node)
+		}
+	}
+
+	results := make(map[string]*TaskGraphResult, len(g.order))
+	ready := make([]*taskGraphNode, 0, len(g.order))
+	for _, node := range g.order {
+		if pendingDeps[node.name] == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	// skip marks the task and all of its downstream tasks as skipped
+	var skip func(node *taskGraphNode, reason string)
+	skip = func(node *taskGraphNode, reason string) {
+		if _, done := results[node.name]; done {
+			return
+		}
+		results[node.name] = &TaskGraphResult{Err: fmt.Errorf("%w: %s", ErrTaskSkipped, reason)}
+		for _, dependent := range dependents[node.name] {
+			skip(dependent, fmt.Sprintf("dependency %q not completed", node.name))
+		}
+	}
+
+	type taskGraphExecResult struct {
+		node   *taskGraphNode
+		result *TaskGraphResult
+	}
+	resultChan := make(chan *taskGraphExecResult, len(g.order))
+	running := 0
+	canceled := false
+	for len(results) < len(g.order) {
+		for len(ready) > 0 && !canceled && (maxConcurrentTasks == 0 || uint(running) < maxConcurrentTasks) {
+			node := ready[0]
+			ready = ready[1:]
+			running++
+			go func() {
+				res := &TaskGraphResult{StartTime: time.Now()}
+				defer func() {
+					if r := recover(); r != nil {
+						res.Err = newPanicError(r, node.index)
+					}
+					res.Duration = time.Since(res.StartTime)
+					resultChan <- &taskGraphExecResult{node: node, result: res}
+				}()
+				if err := ctx.Err(); err != nil {
+					res.Err = err
+					return
+				}
+				res.Err = node.task(ctx)
+			}()
+		}
+		if running == 0 {
+			// Graph canceled, skip the remaining tasks
+			for _, node := range g.order {
+				skip(node, "graph canceled")
+			}
+			break
+		}
+
+		execRes := <-resultChan
+		running--
+		node := execRes.node
+		results[node.name] = execRes.result
+		if execRes.result.Err != nil {
+			if cfg.cancelOnError && !canceled {
+				canceled = true
+				cancel()
+			}
+			for _, dependent := range dependents[node.name] {
+				skip(dependent, fmt.Sprintf("dependency %q failed", node.name))
+			}
+			continue
+		}
+		for _, dependent := range dependents[node.name] {
+			pendingDeps[dependent.name]--
+			if pendingDeps[dependent.name] == 0 {
+				if _, done := results[dependent.name]; !done {
+					ready = append(ready, dependent)
+				}
+			}
+		}
+	}
+
+	var errs []error
+	for _, node := range g.order {
+		err := results[node.name].Err
+		if err != nil && !errors.Is(err, ErrTaskSkipped) {
+			errs = append(errs, fmt.Errorf("task %q: %w", node.name, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_TaskGraph(t *testing.T) {
+	errTest := errors.New("test error")
+
+	type execLog struct {
+		mu    sync.Mutex
+		names []string
+	}
+	newTask := func(log *execLog, name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			log.mu.Lock()
+			log.names = append(log.names, name)
+			log.mu.Unlock()
+			return err
+		}
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		g := NewTaskGraph()
+		assert.Nil(t, g.Add("a", nil))
+		assert.ErrorIs(t, g.Add("a", nil), ErrTaskGraphDuplicate)
+		assert.Nil(t, g.Add("b", nil, "c"))
+		_, err := g.Exec(context.Background(), 0)
+		assert.ErrorIs(t, err, ErrTaskGraphUnknownDep)
+
+		assert.Nil(t, g.Add("c", nil, "d"))
+		assert.Nil(t, g.Add("d", nil, "a", "b"))
+		err = g.Validate()
+		assert.ErrorIs(t, err, ErrTaskGraphCycle)
+		assert.Equal(t, `task dependency cycle: b -> c -> d -> b`, err.Error())
+	})
+
+	t.Run("dependency order", func(t *testing.T) {
+		log := &execLog{}
+		g := NewTaskGraph()
+		assert.Nil(t, g.Add("d", newTask(log, "d", nil), "b", "c"))
+		assert.Nil(t, g.Add("b", newTask(log, "b", nil), "a"))
+		assert.Nil(t, g.Add("c", newTask(log, "c", nil), "a"))
+		assert.Nil(t, g.Add("a", newTask(log, "a", nil)))
+		results, err := g.Exec(context.Background(), 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, len(results))
+		assert.Equal(t, "a", log.names[0])
+		assert.Equal(t, "d", log.names[3])
+		for _, res := range results {
+			assert.Nil(t, res.Err)
+			assert.True(t, res.Duration >= 5*time.Millisecond)
+		}
+		assert.False(t, results["d"].StartTime.Before(results["b"].StartTime.Add(results["b"].Duration)))
+	})
+
+	t.Run("max concurrency", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		g := NewTaskGraph()
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			assert.Nil(t, g.Add(name, func(ctx context.Context) error {
+				n := running.Add(1)
+				if n > maxRunning.Load() {
+					maxRunning.Store(n)
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			}))
+		}
+		_, err := g.Exec(context.Background(), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), maxRunning.Load())
+	})
+
+	t.Run("skip downstream tasks on failure", func(t *testing.T) {
+		log := &execLog{}
+		g := NewTaskGraph()
+		assert.Nil(t, g.Add("a", newTask(log, "a", errTest)))
+		assert.Nil(t, g.Add("b", newTask(log, "b", nil), "a"))
+		assert.Nil(t, g.Add("c", newTask(log, "c", nil), "b"))
+		assert.Nil(t, g.Add("d", newTask(log, "d", nil)))
+		assert.Nil(t, g.Add("e", func(ctx context.Context) error { panic("boom") }))
+		results, err := g.Exec(context.Background(), 0)
+		assert.ErrorIs(t, err, errTest)
+		assert.ErrorIs(t, err, ErrPanic)
+		assert.ErrorIs(t, results["a"].Err, errTest)
+		assert.ErrorIs(t, results["b"].Err, ErrTaskSkipped)
+		assert.ErrorIs(t, results["c"].Err, ErrTaskSkipped)
+		assert.Nil(t, results["d"].Err)
+		assert.ErrorIs(t, results["e"].Err, ErrPanic)
+		assert.ElementsMatch(t, []string{"a", "d"}, log.names)
+	})
+
+	t.Run("cancel graph on failure", func(t *testing.T) {
+		log := &execLog{}
+		g := NewTaskGraph()
+		assert.Nil(t, g.Add("a", newTask(log, "a", errTest)))
+		assert.Nil(t, g.Add("b", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+		assert.Nil(t, g.Add("c", newTask(log, "c", nil)))
+		assert.Nil(t, g.Add("d", newTask(log, "d", nil), "b"))
+		results, err := g.Exec(context.Background(), 2, TaskGraphCancelOnError(true))
+		assert.ErrorIs(t, err, errTest)
+		assert.ErrorIs(t, results["b"].Err, context.Canceled)
+		assert.ErrorIs(t, results["c"].Err, ErrTaskSkipped)
+		assert.ErrorIs(t, results["d"].Err, ErrTaskSkipped)
+		assert.Equal(t, []string{"a"}, log.names)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		log := &execLog{}
+		g := NewTaskGraph()
+		assert.Nil(t, g.Add("a", newTask(log, "a", nil)))
+		assert.Nil(t, g.Add("b", newTask(log, "b", nil), "a"))
+		results, err := g.Exec(ctx, 0)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, results["b"].Err, ErrTaskSkipped)
+		assert.Equal(t, 0, len(log.names))
+	})
+	t.Run("concurrent executions", func(t *testing.T) {
+		var runs atomic.Int32
+		task := func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}
+		g := NewTaskGraph()
+		assert.Nil(t, g.Add("a", task))
+		assert.Nil(t, g.Add("b", task, "a"))
+		assert.Nil(t, g.Add("c", task, "a", "b"))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results, err := g.Exec(context.Background(), 2)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, len(results))
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(30), runs.Load())
+	})
+}
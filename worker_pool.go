@@ -0,0 +1,194 @@
+package gofn
+
+import (
+	"context"
+	"sync"
+)
+
+type WorkerPoolConfig struct {
+	queueSize    uint
+	errorHandler func(error)
+}
+
+type WorkerPoolOption func(*WorkerPoolConfig)
+
+// WorkerPoolQueueSize sets the maximum number of tasks waiting in the queue (default is the number of workers).
+// When the queue is full, Submit() blocks until a slot is available.
+func WorkerPoolQueueSize(queueSize uint) WorkerPoolOption {
+	return func(config *WorkerPoolConfig) {
+		config.queueSize = queueSize
+	}
+}
+
+// WorkerPoolErrorHandler sets a handler to receive errors of the tasks submitted via Submit() or TrySubmit()
+func WorkerPoolErrorHandler(errorHandler func(error)) WorkerPoolOption {
+	return func(config *WorkerPoolConfig) {
+		config.errorHandler = errorHandler
+	}
+}
+
+type workerPoolTask struct {
+	fn   func(ctx context.Context) error
+	done chan error
+}
+
+// WorkerPool executes submitted tasks using a fixed number of worker goroutines
+type WorkerPool struct {
+	cfg       *WorkerPoolConfig
+	taskChan  chan *workerPoolTask
+	closeChan chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	mu        sync.RWMutex
+	closed    bool
+	submitWg  sync.WaitGroup
+	workerWg  sync.WaitGroup
+}
+
+// NewWorkerPool creates a new worker pool and starts the workers.
+// Call Shutdown() to stop the workers when the pool is no longer used.
+func NewWorkerPool(numWorkers uint, options ...WorkerPoolOption) *WorkerPool {
+	if numWorkers == 0 {
+		numWorkers = 1
+	}
+	cfg := &WorkerPoolConfig{
+		queueSize: numWorkers,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &WorkerPool{
+		cfg:       cfg,
+		taskChan:  make(chan *workerPoolTask, cfg.queueSize),
+		closeChan: make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	p.workerWg.Add(int(numWorkers))
+	for i := uint(0); i < numWorkers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *WorkerPool) work() {
+	defer p.workerWg.Done()
+	for task := range p.taskChan {
+		p.runTask(task)
+	}
+}
+
+func (p *WorkerPool) runTask(task *workerPoolTask) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r, -1)
+			}
+		}()
+		// The pool is abandoned, skip the remaining tasks
+		if p.ctx.Err() != nil {
+			return ErrPoolClosed
+		}
+		return task.fn(p.ctx)
+	}()
+
+	if task.done != nil {
+		task.done <- err
+		return
+	}
+	if err != nil && p.cfg.errorHandler != nil {
+		p.cfg.errorHandler(err)
+	}
+}
+
+// enqueue puts a task into the queue. When block is false, returns false if the queue is full.
+func (p *WorkerPool) enqueue(ctx context.Context, task *workerPoolTask, block bool) (bool, error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return false, ErrPoolClosed
+	}
+	p.submitWg.Add(1)
+	p.mu.RUnlock()
+	defer p.submitWg.Done()
+
+	if !block {
+		select {
+		case p.taskChan <- task:
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
+
+	select {
+	case p.taskChan <- task:
+		return true, nil
+	case <-p.closeChan:
+		return false, ErrPoolClosed
+	case <-ctx.Done():
+		return false, ctx.Err() // nolint: wrapcheck
+	}
+}
+
+// Submit puts a task into the queue. If the queue is full, this function blocks until a slot is
+// available or the context is done. Errors returned by the task are passed to the error handler.
+func (p *WorkerPool) Submit(ctx context.Context, task func(ctx context.Context) error) error {
+	_, err := p.enqueue(ctx, &workerPoolTask{fn: task}, true)
+	return err
+}
+
+// SubmitWait puts a task into the queue and waits for it to finish, returns the task error
+func (p *WorkerPool) SubmitWait(ctx context.Context, task func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	if _, err := p.enqueue(ctx, &workerPoolTask{fn: task, done: done}, true); err != nil {
+		return err
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err() // nolint: wrapcheck
+	}
+}
+
+// TrySubmit puts a task into the queue without blocking, returns false if the queue is full
+// or the pool is closed
+func (p *WorkerPool) TrySubmit(task func(ctx context.Context) error) bool {
+	ok, _ := p.enqueue(context.Background(), &workerPoolTask{fn: task}, false)
+	return ok
+}
+
+// Shutdown stops accepting new tasks and waits for the queued tasks to finish.
+// If the context is done before that, the remaining queued tasks are abandoned, the context
+// passed to the running tasks is canceled, and the context error is returned.
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closeChan)
+		go func() {
+			// Close the queue once all blocking submitters have gone
+			p.submitWg.Wait()
+			close(p.taskChan)
+		}()
+	}
+	p.mu.Unlock()
+
+	doneChan := make(chan struct{})
+	go func() {
+		p.workerWg.Wait()
+		close(doneChan)
+	}()
+
+	select {
+	case <-doneChan:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err() // nolint: wrapcheck
+	}
+}
@@ -0,0 +1,121 @@
+package gofn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nolint
+func Test_WorkerPool(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("submit and shutdown", func(t *testing.T) {
+		var count atomic.Int32
+		pool := NewWorkerPool(3, WorkerPoolQueueSize(2))
+		for i := 0; i < 20; i++ {
+			err := pool.Submit(context.Background(), func(ctx context.Context) error {
+				time.Sleep(time.Millisecond)
+				count.Add(1)
+				return nil
+			})
+			assert.Nil(t, err)
+		}
+		assert.Nil(t, pool.Shutdown(context.Background()))
+		assert.Equal(t, int32(20), count.Load())
+
+		// Submitting after shutdown
+		assert.ErrorIs(t, pool.Submit(context.Background(), func(ctx context.Context) error { return nil }),
+			ErrPoolClosed)
+		assert.False(t, pool.TrySubmit(func(ctx context.Context) error { return nil }))
+		// Shutdown again is fine
+		assert.Nil(t, pool.Shutdown(context.Background()))
+	})
+
+	t.Run("submit wait", func(t *testing.T) {
+		pool := NewWorkerPool(2)
+		defer pool.Shutdown(context.Background())
+
+		assert.Nil(t, pool.SubmitWait(context.Background(), func(ctx context.Context) error { return nil }))
+		assert.ErrorIs(t, pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+			return errTest
+		}), errTest)
+		err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+			panic("oops")
+		})
+		assert.ErrorIs(t, err, ErrPanic)
+		var panicErr *PanicError
+		assert.True(t, errors.As(err, &panicErr))
+		assert.Equal(t, "oops", panicErr.Value)
+		assert.Equal(t, -1, panicErr.TaskIndex)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, pool.SubmitWait(ctx, func(ctx context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}), context.DeadlineExceeded)
+	})
+
+	t.Run("try submit with full queue", func(t *testing.T) {
+		blockChan := make(chan struct{})
+		pool := NewWorkerPool(1, WorkerPoolQueueSize(1))
+		task := func(ctx context.Context) error {
+			<-blockChan
+			return nil
+		}
+		assert.Nil(t, pool.Submit(context.Background(), task))
+		time.Sleep(10 * time.Millisecond) // let the worker take the 1st task
+		assert.True(t, pool.TrySubmit(task))
+		assert.False(t, pool.TrySubmit(task))
+
+		// Submit blocks until the context is done
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, pool.Submit(ctx, task), context.DeadlineExceeded)
+
+		close(blockChan)
+		assert.Nil(t, pool.Shutdown(context.Background()))
+	})
+
+	t.Run("error handler", func(t *testing.T) {
+		var mu sync.Mutex
+		var errs []error
+		pool := NewWorkerPool(2, WorkerPoolErrorHandler(func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}))
+		_ = pool.Submit(context.Background(), func(ctx context.Context) error { return errTest })
+		_ = pool.Submit(context.Background(), func(ctx context.Context) error { panic(errTest) })
+		_ = pool.Submit(context.Background(), func(ctx context.Context) error { return nil })
+		assert.Nil(t, pool.Shutdown(context.Background()))
+		assert.Equal(t, 2, len(errs))
+	})
+
+	t.Run("shutdown abandons the queue on timeout", func(t *testing.T) {
+		var count atomic.Int32
+		pool := NewWorkerPool(1, WorkerPoolQueueSize(10))
+		for i := 0; i < 10; i++ {
+			_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(20 * time.Millisecond):
+				}
+				count.Add(1)
+				return nil
+			})
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+		time.Sleep(30 * time.Millisecond)
+		assert.True(t, count.Load() < 10)
+	})
+}